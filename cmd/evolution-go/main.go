@@ -20,13 +20,23 @@ import (
 	call_handler "github.com/EvolutionAPI/evolution-go/pkg/call/handler"
 	call_service "github.com/EvolutionAPI/evolution-go/pkg/call/service"
 	chat_handler "github.com/EvolutionAPI/evolution-go/pkg/chat/handler"
+	chat_model "github.com/EvolutionAPI/evolution-go/pkg/chat/model"
+	chat_repository "github.com/EvolutionAPI/evolution-go/pkg/chat/repository"
 	chat_service "github.com/EvolutionAPI/evolution-go/pkg/chat/service"
 	community_handler "github.com/EvolutionAPI/evolution-go/pkg/community/handler"
 	community_service "github.com/EvolutionAPI/evolution-go/pkg/community/service"
 	config "github.com/EvolutionAPI/evolution-go/pkg/config"
+	event_store_handler "github.com/EvolutionAPI/evolution-go/pkg/eventStore/handler"
+	event_store_model "github.com/EvolutionAPI/evolution-go/pkg/eventStore/model"
+	event_store_repository "github.com/EvolutionAPI/evolution-go/pkg/eventStore/repository"
+	event_store_service "github.com/EvolutionAPI/evolution-go/pkg/eventStore/service"
 	producer_interfaces "github.com/EvolutionAPI/evolution-go/pkg/events/interfaces"
+	kafka_producer "github.com/EvolutionAPI/evolution-go/pkg/events/kafka"
+	mqtt_producer "github.com/EvolutionAPI/evolution-go/pkg/events/mqtt"
 	nats_producer "github.com/EvolutionAPI/evolution-go/pkg/events/nats"
 	rabbitmq_producer "github.com/EvolutionAPI/evolution-go/pkg/events/rabbitmq"
+	redis_producer "github.com/EvolutionAPI/evolution-go/pkg/events/redis"
+	sse_producer "github.com/EvolutionAPI/evolution-go/pkg/events/sse"
 	webhook_producer "github.com/EvolutionAPI/evolution-go/pkg/events/webhook"
 	websocket_producer "github.com/EvolutionAPI/evolution-go/pkg/events/websocket"
 	group_handler "github.com/EvolutionAPI/evolution-go/pkg/group/handler"
@@ -56,6 +66,10 @@ import (
 	"github.com/EvolutionAPI/evolution-go/pkg/telemetry"
 	user_handler "github.com/EvolutionAPI/evolution-go/pkg/user/handler"
 	user_service "github.com/EvolutionAPI/evolution-go/pkg/user/service"
+	webhook_handler "github.com/EvolutionAPI/evolution-go/pkg/webhook/handler"
+	webhook_model "github.com/EvolutionAPI/evolution-go/pkg/webhook/model"
+	webhook_repository "github.com/EvolutionAPI/evolution-go/pkg/webhook/repository"
+	webhook_service "github.com/EvolutionAPI/evolution-go/pkg/webhook/service"
 	whatsmeow_service "github.com/EvolutionAPI/evolution-go/pkg/whatsmeow/service"
 	amqp "github.com/rabbitmq/amqp091-go"
 )
@@ -68,7 +82,7 @@ func setupRouter(db *gorm.DB, authDB *sql.DB, sqliteDB *sql.DB, config *config.C
 
 	loggerWrapper := logger_wrapper.NewLoggerManager(config)
 
-	var rabbitmqProducer producer_interfaces.Producer
+	var rabbitmqProducer producer_interfaces.BrokerProducer
 	if conn != nil {
 		logger.LogInfo("RabbitMQ enabled")
 		rabbitmqProducer = rabbitmq_producer.NewRabbitMQProducer(
@@ -76,6 +90,7 @@ func setupRouter(db *gorm.DB, authDB *sql.DB, sqliteDB *sql.DB, config *config.C
 			config.AmqpGlobalEnabled,
 			config.AmqpGlobalEvents,
 			config.AmqpSpecificEvents,
+			config.AmqpExchange,
 			config.AmqpUrl,
 			loggerWrapper,
 		)
@@ -86,18 +101,32 @@ func setupRouter(db *gorm.DB, authDB *sql.DB, sqliteDB *sql.DB, config *config.C
 			config.AmqpGlobalEnabled,
 			config.AmqpGlobalEvents,
 			config.AmqpSpecificEvents,
+			config.AmqpExchange,
 			config.AmqpUrl, // Keep the URL for reconnection attempts
 			loggerWrapper,
 		)
 	}
 
-	var natsProducer producer_interfaces.Producer
+	var natsProducer producer_interfaces.BrokerProducer
 	if config.NatsUrl != "" {
 		logger.LogInfo("NATS enabled")
 		natsProducer = nats_producer.NewNatsProducer(
 			config.NatsUrl,
 			config.NatsGlobalEnabled,
 			config.NatsGlobalEvents,
+			nats_producer.JetStreamConfig{
+				Enabled:            config.NatsJetStream,
+				Stream:             config.NatsStreamName,
+				Subjects:           config.NatsStreamSubjects,
+				Replicas:           config.NatsStreamReplicas,
+				MaxAge:             config.NatsStreamMaxAge,
+				Retries:            config.NatsPublishRetries,
+				ProvisionDedicated: config.NatsDedicatedStream,
+			},
+			nats_producer.SubjectConfig{
+				GlobalPrefix:     config.NatsSubjectPrefix,
+				InstanceTemplate: config.NatsSubjectTemplate,
+			},
 			loggerWrapper,
 		)
 	} else {
@@ -105,15 +134,68 @@ func setupRouter(db *gorm.DB, authDB *sql.DB, sqliteDB *sql.DB, config *config.C
 			"",
 			false,
 			nil,
+			nats_producer.JetStreamConfig{},
+			nats_producer.SubjectConfig{},
 			loggerWrapper,
 		)
 	}
 
-	webhookProducer := webhook_producer.NewWebhookProducer(config.WebhookUrl, loggerWrapper)
+	if len(config.KafkaBrokers) > 0 {
+		logger.LogInfo("Kafka enabled")
+	}
+	kafkaProducer := kafka_producer.NewKafkaProducer(
+		config.KafkaBrokers,
+		config.KafkaAcks,
+		config.KafkaCompression,
+		loggerWrapper,
+	)
+
+	if config.RedisUrl != "" {
+		logger.LogInfo("Redis Streams enabled")
+	}
+	redisProducer := redis_producer.NewRedisProducer(
+		config.RedisUrl,
+		config.RedisStreamPrefix,
+		config.RedisStreamMaxLen,
+		loggerWrapper,
+	)
+
+	if config.MqttBroker != "" {
+		logger.LogInfo("MQTT enabled")
+	}
+	mqttProducer := mqtt_producer.NewMqttProducer(
+		mqtt_producer.MqttConfig{
+			Broker:      config.MqttBroker,
+			Username:    config.MqttUsername,
+			Password:    config.MqttPassword,
+			ClientId:    config.MqttClientId,
+			Qos:         config.MqttQos,
+			TopicPrefix: config.MqttTopicPrefix,
+			TlsCaFile:   config.MqttTlsCaFile,
+			TlsInsecure: config.MqttTlsInsecure,
+		},
+		loggerWrapper,
+	)
+
+	webhookRepository := webhook_repository.NewWebhookRepository(db)
+	webhookProducer := webhook_producer.NewWebhookProducer(
+		config.WebhookUrl,
+		config.WebhookSecret,
+		config.WebhookPrevSecret,
+		config.EncryptionKey,
+		config.CloudEventsBinary,
+		webhookRepository,
+		config.WebhookMaxAttempts,
+		config.WebhookRetryBase,
+		config.WebhookRetryMax,
+		config.WebhookLogRetention,
+		loggerWrapper,
+	)
 	websocketProducer := websocket_producer.NewWebsocketProducer(loggerWrapper)
+	sseProducer := sse_producer.NewSseProducer(config.SseEnabled, config.SseBufferSize, loggerWrapper)
 
-	// Cria filas globais se o RabbitMQ global estiver habilitado
-	if config.AmqpGlobalEnabled && conn != nil {
+	// Cria filas globais se o RabbitMQ global estiver habilitado, ou declara o exchange no modo exchange
+	if (config.AmqpGlobalEnabled || config.AmqpExchange != "") && conn != nil {
 		logger.LogInfo("Creating global RabbitMQ queues...")
 		if err := rabbitmqProducer.CreateGlobalQueues(); err != nil {
 			logger.LogError("Failed to create global RabbitMQ queues: %v", err)
@@ -122,6 +204,14 @@ func setupRouter(db *gorm.DB, authDB *sql.DB, sqliteDB *sql.DB, config *config.C
 		}
 	}
 
+	// Cria ou valida o stream JetStream se o modo JetStream do NATS estiver habilitado
+	if config.NatsJetStream && config.NatsUrl != "" {
+		logger.LogInfo("Provisioning NATS JetStream stream %s...", config.NatsStreamName)
+		if err := natsProducer.CreateGlobalQueues(); err != nil {
+			logger.LogError("Failed to provision NATS JetStream stream: %v", err)
+		}
+	}
+
 	var mediaStorage storage_interfaces.MediaStorage
 	var err error
 	if config.MinioEnabled {
@@ -141,12 +231,15 @@ func setupRouter(db *gorm.DB, authDB *sql.DB, sqliteDB *sql.DB, config *config.C
 	instanceRepository := instance_repository.NewInstanceRepository(db)
 	messageRepository := message_repository.NewMessageRepository(db)
 	labelRepository := label_repository.NewLabelRepository(db)
+	chatRepository := chat_repository.NewChatRepository(db)
+	eventStoreRepository := event_store_repository.NewEventStoreRepository(db)
 
 	whatsmeowService := whatsmeow_service.NewWhatsmeowService(
 		instanceRepository,
 		authDB,
 		message_repository.NewMessageRepository(db),
 		labelRepository,
+		chatRepository,
 		config,
 		killChannel,
 		clientPointer,
@@ -157,6 +250,12 @@ func setupRouter(db *gorm.DB, authDB *sql.DB, sqliteDB *sql.DB, config *config.C
 		exPath,
 		mediaStorage,
 		natsProducer,
+		kafkaProducer,
+		redisProducer,
+		mqttProducer,
+		sseProducer,
+		webhookRepository,
+		eventStoreRepository,
 		loggerWrapper,
 	)
 	instanceService := instance_service.NewInstanceService(
@@ -167,22 +266,26 @@ func setupRouter(db *gorm.DB, authDB *sql.DB, sqliteDB *sql.DB, config *config.C
 		config,
 		loggerWrapper,
 	)
-	sendMessageService := send_service.NewSendService(clientPointer, whatsmeowService, config, loggerWrapper)
+	sendMessageService := send_service.NewSendService(clientPointer, messageRepository, chatRepository, whatsmeowService, config, loggerWrapper)
 	userService := user_service.NewUserService(clientPointer, whatsmeowService, loggerWrapper)
 	messageService := message_service.NewMessageService(clientPointer, messageRepository, whatsmeowService, loggerWrapper)
-	chatService := chat_service.NewChatService(clientPointer, whatsmeowService, loggerWrapper)
+	websocketProducer.SetCommandHandler(websocket_producer.NewCommandHandler(sendMessageService, messageService, instanceRepository))
+	chatService := chat_service.NewChatService(clientPointer, messageRepository, chatRepository, whatsmeowService, loggerWrapper)
 	groupService := group_service.NewGroupService(clientPointer, whatsmeowService, loggerWrapper)
 	callService := call_service.NewCallService(clientPointer, whatsmeowService, loggerWrapper)
 	communityService := community_service.NewCommunityService(clientPointer, whatsmeowService, loggerWrapper)
 	labelService := label_service.NewLabelService(clientPointer, whatsmeowService, labelRepository, loggerWrapper)
 	newsletterService := newsletter_service.NewNewsletterService(clientPointer, whatsmeowService, loggerWrapper)
+	webhookService := webhook_service.NewWebhookService(webhookRepository, whatsmeowService, loggerWrapper)
+	eventStoreService := event_store_service.NewEventStoreService(eventStoreRepository, whatsmeowService, config, loggerWrapper)
 
 	telemetry := telemetry.NewTelemetryService()
 
 	r := gin.Default()
 	r.Use(telemetry.TelemetryMiddleware())
+	authMiddleware := auth_middleware.NewMiddleware(config, instanceService)
 	routes.NewRouter(
-		auth_middleware.NewMiddleware(config, instanceService),
+		authMiddleware,
 		instance_handler.NewInstanceHandler(instanceService, config),
 		user_handler.NewUserHandler(userService),
 		send_handler.NewSendHandler(sendMessageService),
@@ -194,6 +297,8 @@ func setupRouter(db *gorm.DB, authDB *sql.DB, sqliteDB *sql.DB, config *config.C
 		label_handler.NewLabelHandler(labelService),
 		newsletter_handler.NewNewsletterHandler(newsletterService),
 		server_handler.NewServerHandler(),
+		webhook_handler.NewWebhookHandler(webhookService),
+		event_store_handler.NewEventStoreHandler(eventStoreService, config),
 	).AssignRoutes(r)
 
 	if config.ConnectOnStartup {
@@ -204,20 +309,67 @@ func setupRouter(db *gorm.DB, authDB *sql.DB, sqliteDB *sql.DB, config *config.C
 		token := c.Query("token")
 		instanceId := c.Query("instanceId")
 
-		if token != config.GlobalApiKey {
-			logger.LogError("Token inválido: %s", token)
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Token inválido"})
-			return
+		// A chave global acessa qualquer instância (ou todas, sem instanceId);
+		// o token de uma instância fica restrito aos eventos dela
+		if token == "" || token != config.GlobalApiKey {
+			instance, err := instanceService.GetInstanceByToken(token)
+			if token == "" || err != nil {
+				logger.LogError("Token inválido: %s", token)
+				c.JSON(http.StatusUnauthorized, gin.H{"error": "Token inválido"})
+				return
+			}
+
+			if instanceId != "" && instanceId != instance.Id {
+				c.JSON(http.StatusForbidden, gin.H{"error": "Token não pertence à instância"})
+				return
+			}
+
+			instanceId = instance.Id
 		}
 
 		websocket_producer.ServeWs(c.Writer, c.Request, instanceId, websocketProducer)
 	})
 
+	// Server-Sent Events: stream da instância autenticado pelo apikey da instância
+	// e stream de todas as instâncias (ou de uma, via instanceId) com a chave global
+	r.GET("/events/stream", authMiddleware.Auth, func(c *gin.Context) {
+		instance := c.MustGet("instance").(*instance_model.Instance)
+		sseProducer.Stream(c.Writer, c.Request, instance.Id)
+	})
+	r.GET("/events/stream/all", authMiddleware.AuthAdmin, func(c *gin.Context) {
+		sseProducer.Stream(c.Writer, c.Request, c.Query("instanceId"))
+	})
+
 	return r
 }
 
+// legacyMessageConstraints removem a unicidade global de message_id, que passou a ser única por instância
+// (idx_messages_instance_message). O AutoMigrate não remove essas restrições sozinho; os nomes cobrem
+// a tag unique das versões antigas (messages_message_id_key) e atuais (uni_messages_message_id) do gorm
+var legacyMessageConstraints = []string{
+	"ALTER TABLE IF EXISTS messages DROP CONSTRAINT IF EXISTS uni_messages_message_id",
+	"ALTER TABLE IF EXISTS messages DROP CONSTRAINT IF EXISTS messages_message_id_key",
+	"DROP INDEX IF EXISTS idx_messages_message_id",
+}
+
 func migrate(db *gorm.DB) {
-	err := db.AutoMigrate(&instance_model.Instance{}, &message_model.Message{}, &label_model.Label{})
+	for _, statement := range legacyMessageConstraints {
+		if err := db.Exec(statement).Error; err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	err := db.AutoMigrate(
+		&instance_model.Instance{},
+		&message_model.Message{},
+		&chat_model.Chat{},
+		&label_model.Label{},
+		&webhook_model.InstanceWebhook{},
+		&webhook_model.WebhookOutbox{},
+		&webhook_model.WebhookDeadLetter{},
+		&webhook_model.WebhookDelivery{},
+		&event_store_model.StoredEvent{},
+	)
 
 	if err != nil {
 		log.Fatal(err)
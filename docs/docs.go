@@ -2703,6 +2703,15 @@ const docTemplate = `{
         "github_com_Zapbox-API_evolution-go_pkg_instance_service.ConnectStruct": {
             "type": "object",
             "properties": {
+                "eventFormat": {
+                    "description": "raw, v1 ou cloudevents; vazio usa EVENT_FORMAT",
+                    "type": "string",
+                    "enum": [
+                        "raw",
+                        "v1",
+                        "cloudevents"
+                    ]
+                },
                 "immediate": {
                     "type": "boolean"
                 },
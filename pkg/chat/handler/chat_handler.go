@@ -1,6 +1,7 @@
 package chat_handler
 
 import (
+	"errors"
 	"net/http"
 
 	chat_service "github.com/EvolutionAPI/evolution-go/pkg/chat/service"
@@ -16,6 +17,8 @@ type ChatHandler interface {
 	ChatMute(ctx *gin.Context)
 	ChatUnmute(ctx *gin.Context)
 	HistorySyncRequest(ctx *gin.Context)
+	ListMessages(ctx *gin.Context)
+	ListChats(ctx *gin.Context)
 }
 
 type chatHandler struct {
@@ -328,6 +331,89 @@ func (c *chatHandler) HistorySyncRequest(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, gin.H{"message": "success", "data": resp})
 }
 
+// List chat messages
+// @Summary List chat messages
+// @Description List the stored messages of a chat (requires DATABASE_SAVE_MESSAGES), paginated by cursor
+// @Tags Chat
+// @Produce json
+// @Param chat query string true "Chat JID or number"
+// @Param cursor query string false "nextCursor returned by the previous page"
+// @Param direction query string false "before (default, newest first) or after (oldest first)"
+// @Param limit query int false "Max messages (default 50, max 500)"
+// @Param type query string false "Message types separated by comma (e.g. text,image)"
+// @Param fromMe query bool false "Only messages sent (true) or received (false) by the instance"
+// @Success 200 {object} gin.H "success"
+// @Failure 400 {object} gin.H "Error on validation"
+// @Failure 500 {object} gin.H "Internal server error"
+// @Router /chat/messages [get]
+func (c *chatHandler) ListMessages(ctx *gin.Context) {
+	getInstance := ctx.MustGet("instance")
+
+	instance, ok := getInstance.(*instance_model.Instance)
+	if !ok {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "instance not found"})
+		return
+	}
+
+	var data chat_service.ListMessagesStruct
+	if err := ctx.ShouldBindQuery(&data); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if data.Chat == "" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "chat is required"})
+		return
+	}
+
+	page, err := c.chatService.ListMessages(&data, instance)
+	if err != nil {
+		if errors.Is(err, chat_service.ErrInvalidListMessages) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "success", "data": page})
+}
+
+// List chats
+// @Summary List chats
+// @Description List the chats with name, last message, unread count and archived/pinned/muted flags (requires DATABASE_SAVE_MESSAGES)
+// @Tags Chat
+// @Produce json
+// @Param limit query int false "Max chats (default 100, max 1000)"
+// @Param offset query int false "Chats to skip"
+// @Success 200 {object} gin.H "success"
+// @Failure 400 {object} gin.H "Error on validation"
+// @Failure 500 {object} gin.H "Internal server error"
+// @Router /chat/list [get]
+func (c *chatHandler) ListChats(ctx *gin.Context) {
+	getInstance := ctx.MustGet("instance")
+
+	instance, ok := getInstance.(*instance_model.Instance)
+	if !ok {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "instance not found"})
+		return
+	}
+
+	var data chat_service.ListChatsStruct
+	if err := ctx.ShouldBindQuery(&data); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	chats, err := c.chatService.ListChats(&data, instance)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "success", "data": chats})
+}
+
 func NewChatHandler(
 	chatService chat_service.ChatService,
 ) ChatHandler {
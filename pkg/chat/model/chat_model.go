@@ -0,0 +1,96 @@
+package chat_model
+
+import (
+	"strings"
+	"time"
+
+	message_model "github.com/EvolutionAPI/evolution-go/pkg/message/model"
+	"github.com/google/uuid"
+	"go.mau.fi/whatsmeow/types"
+	"gorm.io/gorm"
+)
+
+// previewLength limita o texto da última mensagem guardado para a lista de conversas
+const previewLength = 100
+
+type Chat struct {
+	Id              string     `json:"id" gorm:"type:uuid;primaryKey"`
+	InstanceId      string     `json:"instance_id" gorm:"type:uuid;uniqueIndex:idx_chats_instance_jid,priority:1"`
+	Jid             string     `json:"jid" gorm:"uniqueIndex:idx_chats_instance_jid,priority:2"`
+	Name            string     `json:"name"`
+	LastMessageId   string     `json:"last_message_id"`
+	LastMessage     string     `json:"last_message"`
+	LastMessageType string     `json:"last_message_type"`
+	LastFromMe      bool       `json:"last_from_me"`
+	LastActivity    time.Time  `json:"last_activity"`
+	UnreadCount     int        `json:"unread_count"`
+	Archived        bool       `json:"archived"`
+	Pinned          bool       `json:"pinned"`
+	Muted           bool       `json:"muted"`
+	MutedUntil      *time.Time `json:"muted_until"` // nil com Muted indica silenciado para sempre
+}
+
+func (c *Chat) BeforeCreate(tx *gorm.DB) (err error) {
+	c.Id = uuid.New().String()
+	return
+}
+
+// IsMuted considera o fim do silêncio, que o WhatsApp não avisa quando expira
+func (c *Chat) IsMuted(now time.Time) bool {
+	return c.Muted && (c.MutedUntil == nil || c.MutedUntil.After(now))
+}
+
+// IsGroup indica se a conversa é um grupo, cujo nome vem do assunto e não do push name
+func (c *Chat) IsGroup() bool {
+	jid, err := types.ParseJID(c.Jid)
+	return err == nil && jid.Server == types.GroupServer
+}
+
+// ignoredTypes não aparecem como última mensagem: complementam outra mensagem ou não são conteúdo
+var ignoredTypes = map[string]bool{
+	"ignore":                    true,
+	"reaction":                  true,
+	"reaction remove":           true,
+	"encrypted reaction":        true,
+	"poll update":               true,
+	"revoke":                    true,
+	"edit":                      true,
+	"disappearing timer change": true,
+}
+
+// FromMessage monta a atividade que uma mensagem gravada gera na conversa. Status e os tipos de
+// ignoredTypes não contam
+func FromMessage(message message_model.Message) (Chat, bool) {
+	if ignoredTypes[message.Type] || strings.HasPrefix(message.Type, "unknown_protocol_") {
+		return Chat{}, false
+	}
+	if message.ChatJid == "" || message.ChatJid == types.StatusBroadcastJID.String() {
+		return Chat{}, false
+	}
+
+	chat := Chat{
+		InstanceId:      message.InstanceId,
+		Jid:             message.ChatJid,
+		LastMessageId:   message.MessageID,
+		LastMessage:     Preview(message.Text),
+		LastMessageType: message.BaseType(),
+		LastFromMe:      message.FromMe,
+		LastActivity:    message.SentAt,
+	}
+
+	if !chat.IsGroup() && !message.FromMe {
+		chat.Name = message.PushName
+	}
+
+	return chat, true
+}
+
+// Preview corta o texto em previewLength caracteres
+func Preview(text string) string {
+	runes := []rune(text)
+	if len(runes) <= previewLength {
+		return text
+	}
+
+	return string(runes[:previewLength]) + "…"
+}
@@ -0,0 +1,93 @@
+package chat_model
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	message_model "github.com/EvolutionAPI/evolution-go/pkg/message/model"
+)
+
+func TestFromMessage(t *testing.T) {
+	sentAt := time.Date(2024, 5, 1, 12, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		message message_model.Message
+		ok      bool
+		chat    Chat
+	}{
+		{
+			name:    "received in private chat uses push name",
+			message: message_model.Message{InstanceId: "inst", MessageID: "1", ChatJid: "5511999999999@s.whatsapp.net", PushName: "Maria", Type: "text", Text: "olá", SentAt: sentAt},
+			ok:      true,
+			chat:    Chat{InstanceId: "inst", Jid: "5511999999999@s.whatsapp.net", Name: "Maria", LastMessageId: "1", LastMessage: "olá", LastMessageType: "text", LastActivity: sentAt},
+		},
+		{
+			name:    "group keeps name empty",
+			message: message_model.Message{InstanceId: "inst", MessageID: "2", ChatJid: "120363000000000000@g.us", PushName: "Maria", Type: "image image/jpeg", Media: &message_model.MessageMedia{Mimetype: "image/jpeg"}, SentAt: sentAt},
+			ok:      true,
+			chat:    Chat{InstanceId: "inst", Jid: "120363000000000000@g.us", LastMessageId: "2", LastMessageType: "image", LastActivity: sentAt},
+		},
+		{
+			name:    "sent by me keeps name empty",
+			message: message_model.Message{InstanceId: "inst", MessageID: "3", ChatJid: "5511999999999@s.whatsapp.net", PushName: "Eu", FromMe: true, Type: "text", Text: "oi", SentAt: sentAt},
+			ok:      true,
+			chat:    Chat{InstanceId: "inst", Jid: "5511999999999@s.whatsapp.net", LastMessageId: "3", LastMessage: "oi", LastMessageType: "text", LastFromMe: true, LastActivity: sentAt},
+		},
+		{
+			name:    "reaction is ignored",
+			message: message_model.Message{ChatJid: "5511999999999@s.whatsapp.net", Type: "reaction"},
+		},
+		{
+			name:    "status is ignored",
+			message: message_model.Message{ChatJid: "status@broadcast", Type: "text"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			chat, ok := FromMessage(tt.message)
+			if ok != tt.ok {
+				t.Fatalf("FromMessage() ok = %v, want %v", ok, tt.ok)
+			}
+			if chat != tt.chat {
+				t.Errorf("FromMessage() = %+v, want %+v", chat, tt.chat)
+			}
+		})
+	}
+}
+
+func TestPreview(t *testing.T) {
+	long := strings.Repeat("á", previewLength+10)
+
+	if got := Preview("curto"); got != "curto" {
+		t.Errorf("Preview() = %q, want %q", got, "curto")
+	}
+	if got := Preview(long); got != strings.Repeat("á", previewLength)+"…" {
+		t.Errorf("Preview() = %q, want %d characters", got, previewLength)
+	}
+}
+
+func TestIsMuted(t *testing.T) {
+	now := time.Now()
+	past := now.Add(-time.Hour)
+	future := now.Add(time.Hour)
+
+	tests := []struct {
+		name string
+		chat Chat
+		want bool
+	}{
+		{"not muted", Chat{}, false},
+		{"muted forever", Chat{Muted: true}, true},
+		{"muted until future", Chat{Muted: true, MutedUntil: &future}, true},
+		{"mute expired", Chat{Muted: true, MutedUntil: &past}, false},
+	}
+
+	for _, tt := range tests {
+		if got := tt.chat.IsMuted(now); got != tt.want {
+			t.Errorf("%s: IsMuted() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
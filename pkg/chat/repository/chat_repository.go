@@ -0,0 +1,91 @@
+package chat_repository
+
+import (
+	chat_model "github.com/EvolutionAPI/evolution-go/pkg/chat/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ChatRepository interface {
+	SaveLastMessage(chat chat_model.Chat, countUnread bool) error
+	UpsertChat(chat chat_model.Chat, columns ...string) error
+	MarkRead(instanceId string, jid string, read bool) error
+	ListChats(instanceId string, limit int, offset int) ([]chat_model.Chat, error)
+}
+
+type chatRepository struct {
+	db *gorm.DB
+}
+
+// lastMessageColumns só são trocadas quando a mensagem é mais recente que a última conhecida,
+// já que mensagens enviadas e recebidas são gravadas de forma assíncrona
+var lastMessageColumns = []string{"last_message_id", "last_message", "last_message_type", "last_from_me", "last_activity"}
+
+// SaveLastMessage registra a mensagem como atividade da conversa. Com countUnread, mensagens recebidas
+// somam no contador de não lidas e mensagens enviadas o zeram, como no celular
+func (c *chatRepository) SaveLastMessage(chat chat_model.Chat, countUnread bool) error {
+	assignments := map[string]interface{}{
+		// O nome vindo do push name só preenche conversas ainda sem nome
+		"name": gorm.Expr("CASE WHEN chats.name = '' THEN excluded.name ELSE chats.name END"),
+	}
+	for _, column := range lastMessageColumns {
+		assignments[column] = gorm.Expr("CASE WHEN excluded.last_activity >= chats.last_activity THEN excluded." + column + " ELSE chats." + column + " END")
+	}
+
+	if countUnread {
+		if !chat.LastFromMe {
+			chat.UnreadCount = 1
+		}
+		assignments["unread_count"] = gorm.Expr("CASE WHEN excluded.last_from_me THEN 0 ELSE chats.unread_count + 1 END")
+	}
+
+	return c.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "instance_id"}, {Name: "jid"}},
+		DoUpdates: clause.Assignments(assignments),
+	}).Create(&chat).Error
+}
+
+// UpsertChat cria a conversa ou atualiza apenas as colunas informadas
+func (c *chatRepository) UpsertChat(chat chat_model.Chat, columns ...string) error {
+	conflict := clause.OnConflict{
+		Columns:   []clause.Column{{Name: "instance_id"}, {Name: "jid"}},
+		DoUpdates: clause.AssignmentColumns(columns),
+	}
+	if len(columns) == 0 {
+		conflict.DoUpdates = nil
+		conflict.DoNothing = true
+	}
+
+	return c.db.Clauses(conflict).Create(&chat).Error
+}
+
+// MarkRead zera as não lidas; marcar como não lida garante ao menos uma
+func (c *chatRepository) MarkRead(instanceId string, jid string, read bool) error {
+	chat := chat_model.Chat{InstanceId: instanceId, Jid: jid}
+	unread := gorm.Expr("0")
+	if !read {
+		chat.UnreadCount = 1
+		unread = gorm.Expr("GREATEST(chats.unread_count, 1)")
+	}
+
+	return c.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "instance_id"}, {Name: "jid"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{"unread_count": unread}),
+	}).Create(&chat).Error
+}
+
+// ListChats ordena como o WhatsApp: fixadas primeiro e depois pela última atividade
+func (c *chatRepository) ListChats(instanceId string, limit int, offset int) ([]chat_model.Chat, error) {
+	var chats []chat_model.Chat
+	err := c.db.Where("instance_id = ?", instanceId).
+		Order("pinned DESC, last_activity DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&chats).Error
+
+	return chats, err
+}
+
+func NewChatRepository(db *gorm.DB) ChatRepository {
+	return &chatRepository{db: db}
+}
@@ -0,0 +1,137 @@
+package chat_repository
+
+import (
+	"testing"
+	"time"
+
+	chat_model "github.com/EvolutionAPI/evolution-go/pkg/chat/model"
+	"github.com/EvolutionAPI/evolution-go/pkg/internal/test_db"
+)
+
+const (
+	instanceId = "6f1c2d1e-9a53-4c4b-8d4e-0b6a4f1f2a10"
+	jid        = "5511999999999@s.whatsapp.net"
+)
+
+func storedChat(t *testing.T, repository ChatRepository) chat_model.Chat {
+	t.Helper()
+
+	chats, err := repository.ListChats(instanceId, 10, 0)
+	if err != nil || len(chats) != 1 {
+		t.Fatalf("ListChats() = %v, %v, want one chat", chats, err)
+	}
+
+	return chats[0]
+}
+
+func TestSaveLastMessage(t *testing.T) {
+	now := time.Now().UTC().Truncate(time.Second)
+
+	message := func(id string, fromMe bool, sentAt time.Time) chat_model.Chat {
+		return chat_model.Chat{InstanceId: instanceId, Jid: jid, LastMessageId: id, LastFromMe: fromMe, LastActivity: sentAt}
+	}
+
+	tests := []struct {
+		name        string
+		messages    []chat_model.Chat
+		countUnread bool
+		lastMessage string
+		unread      int
+	}{
+		{
+			name:        "Received messages count as unread",
+			messages:    []chat_model.Chat{message("a", false, now), message("b", false, now.Add(time.Second))},
+			countUnread: true,
+			lastMessage: "b",
+			unread:      2,
+		},
+		{
+			name:        "Older message does not replace the last message",
+			messages:    []chat_model.Chat{message("b", false, now.Add(time.Second)), message("a", false, now)},
+			countUnread: true,
+			lastMessage: "b",
+			unread:      2,
+		},
+		{
+			name:        "Sent message resets unread",
+			messages:    []chat_model.Chat{message("a", false, now), message("b", false, now.Add(time.Second)), message("c", true, now.Add(2*time.Second))},
+			countUnread: true,
+			lastMessage: "c",
+			unread:      0,
+		},
+		{
+			name:        "History sync does not count unread",
+			messages:    []chat_model.Chat{message("a", false, now), message("b", false, now.Add(time.Second))},
+			countUnread: false,
+			lastMessage: "b",
+			unread:      0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repository := NewChatRepository(test_db.Open(t, &chat_model.Chat{}))
+
+			for _, chat := range tt.messages {
+				if err := repository.SaveLastMessage(chat, tt.countUnread); err != nil {
+					t.Fatalf("SaveLastMessage() error = %v", err)
+				}
+			}
+
+			chat := storedChat(t, repository)
+			if chat.LastMessageId != tt.lastMessage || chat.UnreadCount != tt.unread {
+				t.Errorf("Expected last message %q with %d unread, but got %q with %d", tt.lastMessage, tt.unread, chat.LastMessageId, chat.UnreadCount)
+			}
+		})
+	}
+}
+
+func TestMarkRead(t *testing.T) {
+	tests := []struct {
+		name     string
+		received int
+		read     bool
+		unread   int
+	}{
+		{
+			name:     "Read resets unread",
+			received: 3,
+			read:     true,
+			unread:   0,
+		},
+		{
+			name:     "Unread keeps a higher count",
+			received: 3,
+			read:     false,
+			unread:   3,
+		},
+		{
+			name:     "Unread marks at least one",
+			received: 0,
+			read:     false,
+			unread:   1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repository := NewChatRepository(test_db.Open(t, &chat_model.Chat{}))
+
+			now := time.Now().UTC().Truncate(time.Second)
+			for i := 0; i < tt.received; i++ {
+				chat := chat_model.Chat{InstanceId: instanceId, Jid: jid, LastActivity: now.Add(time.Duration(i) * time.Second)}
+				if err := repository.SaveLastMessage(chat, true); err != nil {
+					t.Fatalf("SaveLastMessage() error = %v", err)
+				}
+			}
+
+			if err := repository.MarkRead(instanceId, jid, tt.read); err != nil {
+				t.Fatalf("MarkRead() error = %v", err)
+			}
+
+			if chat := storedChat(t, repository); chat.UnreadCount != tt.unread {
+				t.Errorf("Expected %d unread, but got %d", tt.unread, chat.UnreadCount)
+			}
+		})
+	}
+}
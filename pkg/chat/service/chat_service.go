@@ -3,10 +3,16 @@ package chat_service
 import (
 	"context"
 	"errors"
+	"fmt"
+	"strings"
 	"time"
 
+	chat_model "github.com/EvolutionAPI/evolution-go/pkg/chat/model"
+	chat_repository "github.com/EvolutionAPI/evolution-go/pkg/chat/repository"
 	instance_model "github.com/EvolutionAPI/evolution-go/pkg/instance/model"
 	logger_wrapper "github.com/EvolutionAPI/evolution-go/pkg/logger"
+	message_model "github.com/EvolutionAPI/evolution-go/pkg/message/model"
+	message_repository "github.com/EvolutionAPI/evolution-go/pkg/message/repository"
 	"github.com/EvolutionAPI/evolution-go/pkg/utils"
 	whatsmeow_service "github.com/EvolutionAPI/evolution-go/pkg/whatsmeow/service"
 	"go.mau.fi/whatsmeow"
@@ -22,12 +28,16 @@ type ChatService interface {
 	ChatMute(data *BodyStruct, instance *instance_model.Instance) (string, error)
 	ChatUnmute(data *BodyStruct, instance *instance_model.Instance) (string, error)
 	HistorySyncRequest(data *HistorySyncRequestStruct, instance *instance_model.Instance) (*whatsmeow.SendResponse, error)
+	ListMessages(data *ListMessagesStruct, instance *instance_model.Instance) (*MessagePage, error)
+	ListChats(data *ListChatsStruct, instance *instance_model.Instance) ([]ChatSummary, error)
 }
 
 type chatService struct {
-	clientPointer    map[string]*whatsmeow.Client
-	whatsmeowService whatsmeow_service.WhatsmeowService
-	loggerWrapper    *logger_wrapper.LoggerManager
+	clientPointer     map[string]*whatsmeow.Client
+	messageRepository message_repository.MessageRepository
+	chatRepository    chat_repository.ChatRepository
+	whatsmeowService  whatsmeow_service.WhatsmeowService
+	loggerWrapper     *logger_wrapper.LoggerManager
 }
 
 type BodyStruct struct {
@@ -39,6 +49,61 @@ type HistorySyncRequestStruct struct {
 	Count       int                `json:"count"`
 }
 
+type ListMessagesStruct struct {
+	Chat      string `form:"chat"`
+	Cursor    string `form:"cursor"`
+	Direction string `form:"direction"`
+	Limit     int    `form:"limit"`
+	Type      string `form:"type"`
+	FromMe    *bool  `form:"fromMe"`
+}
+
+type ChatMessage struct {
+	Id        string                      `json:"id"`
+	Chat      string                      `json:"chat"`
+	Sender    string                      `json:"sender"`
+	PushName  string                      `json:"pushName,omitempty"`
+	FromMe    bool                        `json:"fromMe"`
+	Type      string                      `json:"type"`
+	Text      string                      `json:"text,omitempty"`
+	QuotedId  string                      `json:"quotedId,omitempty"`
+	Media     *message_model.MessageMedia `json:"media,omitempty"`
+	Status    string                      `json:"status"`
+	Timestamp time.Time                   `json:"timestamp"`
+}
+
+type MessagePage struct {
+	Messages   []ChatMessage `json:"messages"`
+	NextCursor string        `json:"nextCursor,omitempty"`
+}
+
+var ErrInvalidListMessages = errors.New("invalid message query")
+
+type ListChatsStruct struct {
+	Limit  int `form:"limit"`
+	Offset int `form:"offset"`
+}
+
+type ChatSummary struct {
+	Jid          string           `json:"jid"`
+	Name         string           `json:"name"`
+	IsGroup      bool             `json:"isGroup"`
+	LastMessage  *ChatLastMessage `json:"lastMessage,omitempty"`
+	LastActivity *time.Time       `json:"lastActivity,omitempty"`
+	UnreadCount  int              `json:"unreadCount"`
+	Archived     bool             `json:"archived"`
+	Pinned       bool             `json:"pinned"`
+	Muted        bool             `json:"muted"`
+	MutedUntil   *time.Time       `json:"mutedUntil,omitempty"`
+}
+
+type ChatLastMessage struct {
+	Id     string `json:"id"`
+	Type   string `json:"type"`
+	Text   string `json:"text,omitempty"`
+	FromMe bool   `json:"fromMe"`
+}
+
 func (c *chatService) ensureClientConnected(instanceId string) (*whatsmeow.Client, error) {
 	client := c.clientPointer[instanceId]
 	c.loggerWrapper.GetLogger(instanceId).LogInfo("[%s] Checking client connection status - Client exists: %v", instanceId, client != nil)
@@ -243,14 +308,212 @@ func (c *chatService) HistorySyncRequest(data *HistorySyncRequestStruct, instanc
 	return &res, nil
 }
 
+// ListMessages lê o histórico gravado com DATABASE_SAVE_MESSAGES, sem depender da sessão estar conectada
+func (c *chatService) ListMessages(data *ListMessagesStruct, instance *instance_model.Instance) (*MessagePage, error) {
+	chat, ok := utils.ParseJID(data.Chat)
+	if !ok {
+		return nil, fmt.Errorf("%w: invalid chat", ErrInvalidListMessages)
+	}
+
+	filter := message_repository.MessageFilter{
+		ChatJid: chat.ToNonAD().String(),
+		FromMe:  data.FromMe,
+		Limit:   data.Limit,
+	}
+
+	switch strings.ToLower(data.Direction) {
+	case "", "before":
+	case "after":
+		filter.After = true
+	default:
+		return nil, fmt.Errorf("%w: direction must be before or after", ErrInvalidListMessages)
+	}
+
+	if data.Cursor != "" {
+		cursor, err := message_model.ParseMessageCursor(data.Cursor)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidListMessages, err)
+		}
+		filter.Cursor = cursor
+	}
+
+	for _, messageType := range strings.Split(data.Type, ",") {
+		if messageType = strings.ToLower(strings.TrimSpace(messageType)); messageType != "" {
+			filter.Types = append(filter.Types, messageType)
+		}
+	}
+
+	if filter.Limit <= 0 {
+		filter.Limit = 50 // Default: 50 mensagens
+	}
+	if filter.Limit > 500 {
+		filter.Limit = 500
+	}
+
+	// Busca um registro a mais para saber se existe próxima página
+	limit := filter.Limit
+	filter.Limit++
+
+	messages, err := c.messageRepository.ListMessages(instance.Id, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &MessagePage{Messages: []ChatMessage{}}
+	if len(messages) > limit {
+		messages = messages[:limit]
+		last := messages[limit-1]
+		page.NextCursor = message_model.MessageCursor{SentAt: last.SentAt, MessageID: last.MessageID}.Encode()
+	}
+
+	for _, message := range messages {
+		page.Messages = append(page.Messages, ChatMessage{
+			Id:        message.MessageID,
+			Chat:      message.ChatJid,
+			Sender:    message.Sender,
+			PushName:  message.PushName,
+			FromMe:    message.FromMe,
+			Type:      message.BaseType(),
+			Text:      message.Text,
+			QuotedId:  message.QuotedId,
+			Media:     message.Media,
+			Status:    message.Status,
+			Timestamp: message.SentAt,
+		})
+	}
+
+	return page, nil
+}
+
+// ListChats monta a lista de conversas a partir da tabela chats, mantida com DATABASE_SAVE_MESSAGES
+// pelas mensagens gravadas, pelo history sync e pelos eventos de app state
+func (c *chatService) ListChats(data *ListChatsStruct, instance *instance_model.Instance) ([]ChatSummary, error) {
+	limit := data.Limit
+	if limit <= 0 {
+		limit = 100 // Default: 100 conversas
+	}
+	if limit > 1000 {
+		limit = 1000
+	}
+
+	chats, err := c.chatRepository.ListChats(instance.Id, limit, max(data.Offset, 0))
+	if err != nil {
+		return nil, err
+	}
+
+	c.fillChatNames(instance.Id, chats)
+
+	now := time.Now()
+	summaries := make([]ChatSummary, 0, len(chats))
+	for _, chat := range chats {
+		summary := ChatSummary{
+			Jid:         chat.Jid,
+			Name:        chat.Name,
+			IsGroup:     chat.IsGroup(),
+			UnreadCount: chat.UnreadCount,
+			Archived:    chat.Archived,
+			Pinned:      chat.Pinned,
+			Muted:       chat.IsMuted(now),
+		}
+		if summary.Muted {
+			summary.MutedUntil = chat.MutedUntil
+		}
+		if !chat.LastActivity.IsZero() && chat.LastActivity.Unix() > 0 {
+			lastActivity := chat.LastActivity
+			summary.LastActivity = &lastActivity
+		}
+		if chat.LastMessageId != "" {
+			summary.LastMessage = &ChatLastMessage{
+				Id:     chat.LastMessageId,
+				Type:   chat.LastMessageType,
+				Text:   chat.LastMessage,
+				FromMe: chat.LastFromMe,
+			}
+		}
+
+		summaries = append(summaries, summary)
+	}
+
+	return summaries, nil
+}
+
+// fillChatNames completa as conversas sem nome com a agenda da sessão e, para grupos, com o assunto,
+// que é gravado para as próximas consultas
+func (c *chatService) fillChatNames(instanceId string, chats []chat_model.Chat) {
+	client := c.clientPointer[instanceId]
+	if client == nil || client.Store == nil || client.Store.Contacts == nil {
+		return
+	}
+
+	var groupNames map[string]string
+	for i := range chats {
+		if chats[i].Name != "" {
+			continue
+		}
+
+		if chats[i].IsGroup() {
+			if groupNames == nil {
+				groupNames = c.joinedGroupNames(instanceId, client)
+			}
+			if name := groupNames[chats[i].Jid]; name != "" {
+				chats[i].Name = name
+				if err := c.chatRepository.UpsertChat(chat_model.Chat{InstanceId: instanceId, Jid: chats[i].Jid, Name: name}, "name"); err != nil {
+					c.loggerWrapper.GetLogger(instanceId).LogError("[%s] Failed to save chat name %s: %v", instanceId, chats[i].Jid, err)
+				}
+			}
+			continue
+		}
+
+		jid, err := types.ParseJID(chats[i].Jid)
+		if err != nil {
+			continue
+		}
+
+		contact, err := client.Store.Contacts.GetContact(context.Background(), jid)
+		if err != nil || !contact.Found {
+			continue
+		}
+
+		for _, name := range []string{contact.FullName, contact.FirstName, contact.PushName, contact.BusinessName} {
+			if name != "" {
+				chats[i].Name = name
+				break
+			}
+		}
+	}
+}
+
+func (c *chatService) joinedGroupNames(instanceId string, client *whatsmeow.Client) map[string]string {
+	names := make(map[string]string)
+	if !client.IsConnected() {
+		return names
+	}
+
+	groups, err := client.GetJoinedGroups(context.Background())
+	if err != nil {
+		c.loggerWrapper.GetLogger(instanceId).LogWarn("[%s] Failed to get joined groups for chat names: %v", instanceId, err)
+		return names
+	}
+
+	for _, group := range groups {
+		names[group.JID.String()] = group.Name
+	}
+
+	return names
+}
+
 func NewChatService(
 	clientPointer map[string]*whatsmeow.Client,
+	messageRepository message_repository.MessageRepository,
+	chatRepository chat_repository.ChatRepository,
 	whatsmeowService whatsmeow_service.WhatsmeowService,
 	loggerWrapper *logger_wrapper.LoggerManager,
 ) ChatService {
 	return &chatService{
-		clientPointer:    clientPointer,
-		whatsmeowService: whatsmeowService,
-		loggerWrapper:    loggerWrapper,
+		clientPointer:     clientPointer,
+		messageRepository: messageRepository,
+		chatRepository:    chatRepository,
+		whatsmeowService:  whatsmeowService,
+		loggerWrapper:     loggerWrapper,
 	}
 }
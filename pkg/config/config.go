@@ -26,6 +26,7 @@ type Config struct {
 	PostgresDB           string
 	DatabaseSaveMessages bool
 	GlobalApiKey         string
+	EncryptionKey        string
 	WaDebug              string
 	LogType              string
 	WebhookFiles         bool
@@ -34,6 +35,13 @@ type Config struct {
 	AmqpUrl              string
 	AmqpGlobalEnabled    bool
 	WebhookUrl           string
+	WebhookSecret        string
+	WebhookPrevSecret    string
+	WebhookSecretOverlap time.Duration
+	WebhookMaxAttempts   int
+	WebhookRetryBase     time.Duration
+	WebhookRetryMax      time.Duration
+	WebhookLogRetention  time.Duration
 	ClientName           string
 	ApiAudioConverter    string
 	ApiAudioConverterKey string
@@ -53,9 +61,46 @@ type Config struct {
 	ProxyPassword        string
 	AmqpGlobalEvents     []string
 	AmqpSpecificEvents   []string
+	AmqpExchange         string
 	NatsUrl              string
 	NatsGlobalEnabled    bool
 	NatsGlobalEvents     []string
+	NatsSpecificEvents   []string
+	NatsSubjectPrefix    string
+	NatsSubjectTemplate  string
+	NatsJetStream        bool
+	NatsStreamName       string
+	NatsStreamSubjects   []string
+	NatsStreamReplicas   int
+	NatsStreamMaxAge     time.Duration
+	NatsPublishRetries   int
+	NatsDedicatedStream  bool
+	KafkaBrokers         []string
+	KafkaGlobalEnabled   bool
+	KafkaGlobalEvents    []string
+	KafkaAcks            string
+	KafkaCompression     string
+	RedisUrl             string
+	RedisStreamPrefix    string
+	RedisStreamMaxLen    int64
+	RedisGlobalEnabled   bool
+	RedisGlobalEvents    []string
+	MqttBroker           string
+	MqttUsername         string
+	MqttPassword         string
+	MqttClientId         string
+	MqttQos              byte
+	MqttTopicPrefix      string
+	MqttTlsCaFile        string
+	MqttTlsInsecure      bool
+	MqttGlobalEnabled    bool
+	MqttGlobalEvents     []string
+	SseEnabled           bool
+	SseBufferSize        int
+	EventFormat          string
+	CloudEventsBinary    bool
+	EventStoreEnabled    bool
+	EventStoreRetention  time.Duration
 	EventIgnoreGroup     bool
 	EventIgnoreStatus    bool
 	QrcodeMaxCount       int
@@ -69,6 +114,11 @@ type Config struct {
 	LogCompress   bool
 }
 
+// GlobalEventsEnabled indica se algum transporte tem publicação global habilitada
+func (c *Config) GlobalEventsEnabled() bool {
+	return c.AmqpGlobalEnabled || c.NatsGlobalEnabled || c.KafkaGlobalEnabled || c.RedisGlobalEnabled || c.MqttGlobalEnabled
+}
+
 func (c *Config) CreateUsersDB() (*gorm.DB, error) {
 	logger.LogDebug("Connecting to database on: %s", c.postgresUsersDB)
 
@@ -149,6 +199,14 @@ func Load() *Config {
 	globalApiKey := os.Getenv(config_env.GLOBAL_API_KEY)
 	panicIfEmpty(config_env.GLOBAL_API_KEY, globalApiKey)
 
+	// Chave das credenciais gravadas nas instâncias; sem ela a GLOBAL_API_KEY é usada, e trocar a API key
+	// deixa de abrir o que já foi cifrado
+	encryptionKey := os.Getenv(config_env.ENCRYPTION_KEY)
+	if encryptionKey == "" {
+		logger.LogWarn("[CONFIG] %s not set, stored credentials are encrypted with %s; rotating %s will make them unreadable", config_env.ENCRYPTION_KEY, config_env.GLOBAL_API_KEY, config_env.GLOBAL_API_KEY)
+		encryptionKey = globalApiKey
+	}
+
 	clientName := os.Getenv(config_env.CLIENT_NAME)
 
 	waDebug := os.Getenv(config_env.WA_DEBUG)
@@ -177,6 +235,27 @@ func Load() *Config {
 	amqpGlobalEnabled := os.Getenv(config_env.AMQP_GLOBAL_ENABLED)
 
 	webhookUrl := os.Getenv(config_env.WEBHOOK_URL)
+	webhookSecret := os.Getenv(config_env.WEBHOOK_SECRET)
+	webhookPrevSecret := os.Getenv(config_env.WEBHOOK_SECRET_PREVIOUS)
+
+	webhookSecretOverlap := envInt(config_env.WEBHOOK_SECRET_OVERLAP, 24) // Padrão de 24 horas aceitando o segredo anterior após uma rotação
+
+	webhookMaxAttempts := envInt(config_env.WEBHOOK_MAX_ATTEMPTS, 0)
+	if webhookMaxAttempts <= 0 {
+		webhookMaxAttempts = 10 // Tentativas antes de mover a entrega para a dead letter
+	}
+
+	webhookRetryBase := envInt(config_env.WEBHOOK_RETRY_BASE, 0)
+	if webhookRetryBase <= 0 {
+		webhookRetryBase = 30 // Default 30 segundos, dobrando a cada tentativa
+	}
+
+	webhookRetryMax := envInt(config_env.WEBHOOK_RETRY_MAX, 0)
+	if webhookRetryMax <= 0 {
+		webhookRetryMax = 3600 // Default 1 hora entre tentativas
+	}
+
+	webhookLogRetention := envInt(config_env.WEBHOOK_LOG_RETENTION, 168) // Default 7 dias de log de entregas; 0 mantém o log indefinidamente
 
 	apiAudioConverter := os.Getenv(config_env.API_AUDIO_CONVERTER)
 	apiAudioConverterKey := os.Getenv(config_env.API_AUDIO_CONVERTER_KEY)
@@ -234,6 +313,89 @@ func Load() *Config {
 	if len(natsGlobalEvents) == 1 && natsGlobalEvents[0] == "" {
 		natsGlobalEvents = []string{}
 	}
+	natsSpecificEvents := strings.Split(os.Getenv(config_env.NATS_SPECIFIC_EVENTS), ",")
+	if len(natsSpecificEvents) == 1 && natsSpecificEvents[0] == "" {
+		natsSpecificEvents = []string{}
+	}
+	natsSubjectPrefix := strings.Trim(os.Getenv(config_env.NATS_SUBJECT_PREFIX), ".")
+	natsSubjectTemplate := os.Getenv(config_env.NATS_SUBJECT_TEMPLATE)
+	if natsSubjectTemplate != "" && !strings.Contains(natsSubjectTemplate, "{event}") {
+		logger.LogWarn("Invalid NATS_SUBJECT_TEMPLATE %q, it must contain {event}; using default", natsSubjectTemplate)
+		natsSubjectTemplate = ""
+	}
+	natsStreamName := os.Getenv(config_env.NATS_JETSTREAM_STREAM)
+	if natsStreamName == "" {
+		natsStreamName = "EVOLUTION"
+	}
+	var natsStreamSubjects []string
+	for _, subject := range strings.Split(os.Getenv(config_env.NATS_JETSTREAM_SUBJECTS), ",") {
+		if subject = strings.TrimSpace(subject); subject != "" {
+			natsStreamSubjects = append(natsStreamSubjects, subject)
+		}
+	}
+	natsStreamReplicas := envInt(config_env.NATS_JETSTREAM_REPLICAS, 1)
+	natsStreamMaxAge := 72 * time.Hour // Default 72h de retenção
+	if maxAge := os.Getenv(config_env.NATS_JETSTREAM_MAX_AGE); maxAge != "" {
+		parsed, err := time.ParseDuration(maxAge)
+		if err != nil {
+			logger.LogFatal("[CONFIG] variable %s must be a duration (e.g. 72h), got %q", config_env.NATS_JETSTREAM_MAX_AGE, maxAge)
+		}
+		natsStreamMaxAge = parsed
+	}
+	natsPublishRetries := envInt(config_env.NATS_JETSTREAM_RETRIES, 3)
+
+	var kafkaBrokers []string
+	for _, broker := range strings.Split(os.Getenv(config_env.KAFKA_BROKERS), ",") {
+		if broker = strings.TrimSpace(broker); broker != "" {
+			kafkaBrokers = append(kafkaBrokers, broker)
+		}
+	}
+	kafkaGlobalEnabled := os.Getenv(config_env.KAFKA_GLOBAL_ENABLED)
+	kafkaGlobalEvents := strings.Split(os.Getenv(config_env.KAFKA_GLOBAL_EVENTS), ",")
+	if len(kafkaGlobalEvents) == 1 && kafkaGlobalEvents[0] == "" {
+		kafkaGlobalEvents = []string{}
+	}
+
+	redisStreamPrefix := os.Getenv(config_env.REDIS_STREAM_PREFIX)
+	if redisStreamPrefix == "" {
+		redisStreamPrefix = "evolution"
+	}
+	redisStreamMaxLen := int64(envInt(config_env.REDIS_STREAM_MAXLEN, 10000)) // Default 10000 eventos por stream (trim aproximado)
+	redisGlobalEnabled := os.Getenv(config_env.REDIS_GLOBAL_ENABLED)
+	redisGlobalEvents := strings.Split(os.Getenv(config_env.REDIS_GLOBAL_EVENTS), ",")
+	if len(redisGlobalEvents) == 1 && redisGlobalEvents[0] == "" {
+		redisGlobalEvents = []string{}
+	}
+
+	mqttClientId := os.Getenv(config_env.MQTT_CLIENT_ID)
+	if mqttClientId == "" {
+		hostname, _ := os.Hostname()
+		mqttClientId = "evolution-go-" + hostname
+	}
+	mqttQos := envInt(config_env.MQTT_QOS, 1) // Default QoS 1 (pelo menos uma entrega)
+	if mqttQos < 0 || mqttQos > 2 {
+		logger.LogFatal("[CONFIG] variable %s must be 0, 1 or 2, got %d", config_env.MQTT_QOS, mqttQos)
+	}
+	mqttTopicPrefix := os.Getenv(config_env.MQTT_TOPIC_PREFIX)
+	if mqttTopicPrefix == "" {
+		mqttTopicPrefix = "evolution"
+	}
+	mqttGlobalEnabled := os.Getenv(config_env.MQTT_GLOBAL_ENABLED)
+	mqttGlobalEvents := strings.Split(os.Getenv(config_env.MQTT_GLOBAL_EVENTS), ",")
+	if len(mqttGlobalEvents) == 1 && mqttGlobalEvents[0] == "" {
+		mqttGlobalEvents = []string{}
+	}
+
+	sseBufferSize := envInt(config_env.SSE_BUFFER_SIZE, 100) // Default 100 eventos por instância para retomada via Last-Event-ID
+
+	// Formato padrão dos eventos: raw, v1 ou cloudevents. Vale para as instâncias sem eventFormat e para as filas globais
+	eventFormat := strings.ToLower(os.Getenv(config_env.EVENT_FORMAT))
+	if eventFormat != "" && eventFormat != "raw" && eventFormat != "v1" && eventFormat != "cloudevents" {
+		logger.LogWarn("Invalid EVENT_FORMAT %q, using raw", eventFormat)
+		eventFormat = ""
+	}
+
+	eventStoreRetention := envInt(config_env.EVENT_STORE_RETENTION, 168) // Default 7 dias de histórico de eventos; 0 mantém os eventos indefinidamente
 
 	// Logger configurations
 	logMaxSize, _ := strconv.Atoi(os.Getenv(config_env.LOG_MAX_SIZE))
@@ -266,6 +428,7 @@ func Load() *Config {
 		postgresUsersDB:      postgresUsersDB,
 		DatabaseSaveMessages: databaseSaveMessages == "true",
 		GlobalApiKey:         globalApiKey,
+		EncryptionKey:        encryptionKey,
 		WaDebug:              waDebug,
 		LogType:              logType,
 		WebhookFiles:         webhookFiles == "true",
@@ -274,6 +437,13 @@ func Load() *Config {
 		AmqpUrl:              amqpUrl,
 		AmqpGlobalEnabled:    amqpGlobalEnabled == "true",
 		WebhookUrl:           webhookUrl,
+		WebhookSecret:        webhookSecret,
+		WebhookPrevSecret:    webhookPrevSecret,
+		WebhookSecretOverlap: time.Duration(webhookSecretOverlap) * time.Hour,
+		WebhookMaxAttempts:   webhookMaxAttempts,
+		WebhookRetryBase:     time.Duration(webhookRetryBase) * time.Second,
+		WebhookRetryMax:      time.Duration(webhookRetryMax) * time.Second,
+		WebhookLogRetention:  time.Duration(webhookLogRetention) * time.Hour,
 		ClientName:           clientName,
 		ApiAudioConverter:    apiAudioConverter,
 		ApiAudioConverterKey: apiAudioConverterKey,
@@ -295,9 +465,46 @@ func Load() *Config {
 		CheckUserExists:      checkUserExists != "false", // Default true, set to false to disable
 		AmqpGlobalEvents:     amqpGlobalEvents,
 		AmqpSpecificEvents:   amqpSpecificEvents,
+		AmqpExchange:         os.Getenv(config_env.AMQP_EXCHANGE),
 		NatsUrl:              natsUrl,
 		NatsGlobalEnabled:    natsGlobalEnabled == "true",
 		NatsGlobalEvents:     natsGlobalEvents,
+		NatsSpecificEvents:   natsSpecificEvents,
+		NatsSubjectPrefix:    natsSubjectPrefix,
+		NatsSubjectTemplate:  natsSubjectTemplate,
+		NatsJetStream:        os.Getenv(config_env.NATS_JETSTREAM_ENABLED) == "true",
+		NatsStreamName:       natsStreamName,
+		NatsStreamSubjects:   natsStreamSubjects,
+		NatsStreamReplicas:   natsStreamReplicas,
+		NatsStreamMaxAge:     natsStreamMaxAge,
+		NatsPublishRetries:   natsPublishRetries,
+		NatsDedicatedStream:  os.Getenv(config_env.NATS_DEDICATED_STREAM) == "true",
+		KafkaBrokers:         kafkaBrokers,
+		KafkaGlobalEnabled:   kafkaGlobalEnabled == "true",
+		KafkaGlobalEvents:    kafkaGlobalEvents,
+		KafkaAcks:            os.Getenv(config_env.KAFKA_ACKS),
+		KafkaCompression:     os.Getenv(config_env.KAFKA_COMPRESSION),
+		RedisUrl:             os.Getenv(config_env.REDIS_URL),
+		RedisStreamPrefix:    redisStreamPrefix,
+		RedisStreamMaxLen:    redisStreamMaxLen,
+		RedisGlobalEnabled:   redisGlobalEnabled == "true",
+		RedisGlobalEvents:    redisGlobalEvents,
+		MqttBroker:           os.Getenv(config_env.MQTT_BROKER),
+		MqttUsername:         os.Getenv(config_env.MQTT_USERNAME),
+		MqttPassword:         os.Getenv(config_env.MQTT_PASSWORD),
+		MqttClientId:         mqttClientId,
+		MqttQos:              byte(mqttQos),
+		MqttTopicPrefix:      mqttTopicPrefix,
+		MqttTlsCaFile:        os.Getenv(config_env.MQTT_TLS_CA_FILE),
+		MqttTlsInsecure:      os.Getenv(config_env.MQTT_TLS_INSECURE) == "true",
+		MqttGlobalEnabled:    mqttGlobalEnabled == "true",
+		MqttGlobalEvents:     mqttGlobalEvents,
+		SseEnabled:           os.Getenv(config_env.SSE_ENABLED) == "true",
+		SseBufferSize:        sseBufferSize,
+		EventFormat:          eventFormat,
+		CloudEventsBinary:    os.Getenv(config_env.CLOUDEVENTS_HTTP_MODE) == "binary",
+		EventStoreEnabled:    os.Getenv(config_env.EVENT_STORE_ENABLED) == "true",
+		EventStoreRetention:  time.Duration(eventStoreRetention) * time.Hour,
 		LogMaxSize:           logMaxSize,
 		LogMaxBackups:        logMaxBackups,
 		LogMaxAge:            logMaxAge,
@@ -339,6 +546,23 @@ func loadMinioConfig(config *Config) {
 	config.MinioRegion = minioRegion
 }
 
+// envInt lê um inteiro da variável, usando def quando ela está vazia. Um valor inválido interrompe a
+// inicialização, para que um erro de digitação não vire 0 ou o padrão sem aviso. As variáveis
+// numéricas anteriores (WHATSAPP_VERSION_*, QRCODE_MAX_COUNT, LOG_MAX_*) mantêm a leitura tolerante
+func envInt(key string, def int) int {
+	value := strings.TrimSpace(os.Getenv(key))
+	if value == "" {
+		return def
+	}
+
+	parsed, err := strconv.Atoi(value)
+	if err != nil {
+		logger.LogFatal("[CONFIG] variable %s must be an integer, got %q", key, value)
+	}
+
+	return parsed
+}
+
 func panicIfEmpty(key, value string) {
 	if value == "" {
 		if os.Getenv("DEBUG_ENABLED") != "1" {
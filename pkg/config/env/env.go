@@ -10,6 +10,7 @@ const (
 	POSTGRES_DB             = "POSTGRES_DB"
 	DATABASE_SAVE_MESSAGES  = "DATABASE_SAVE_MESSAGES"
 	GLOBAL_API_KEY          = "GLOBAL_API_KEY"
+	ENCRYPTION_KEY          = "ENCRYPTION_KEY"
 	WA_DEBUG                = "DEBUG_ENABLED"
 	LOGTYPE                 = "LOG_TYPE"
 	WEBHOOKFILES            = "WEBHOOK_FILES"
@@ -19,7 +20,15 @@ const (
 	AMQP_GLOBAL_ENABLED     = "AMQP_GLOBAL_ENABLED"
 	AMQP_GLOBAL_EVENTS      = "AMQP_GLOBAL_EVENTS"
 	AMQP_SPECIFIC_EVENTS    = "AMQP_SPECIFIC_EVENTS"
+	AMQP_EXCHANGE           = "AMQP_EXCHANGE"
 	WEBHOOK_URL             = "WEBHOOK_URL"
+	WEBHOOK_SECRET          = "WEBHOOK_SECRET"
+	WEBHOOK_SECRET_PREVIOUS = "WEBHOOK_SECRET_PREVIOUS"
+	WEBHOOK_SECRET_OVERLAP  = "WEBHOOK_SECRET_OVERLAP_HOURS"
+	WEBHOOK_MAX_ATTEMPTS    = "WEBHOOK_MAX_ATTEMPTS"
+	WEBHOOK_RETRY_BASE      = "WEBHOOK_RETRY_BASE_SECONDS"
+	WEBHOOK_RETRY_MAX       = "WEBHOOK_RETRY_MAX_SECONDS"
+	WEBHOOK_LOG_RETENTION   = "WEBHOOK_LOG_RETENTION_HOURS"
 	CLIENT_NAME             = "CLIENT_NAME"
 	API_AUDIO_CONVERTER     = "API_AUDIO_CONVERTER"
 	API_AUDIO_CONVERTER_KEY = "API_AUDIO_CONVERTER_KEY"
@@ -40,6 +49,42 @@ const (
 	NATS_URL                = "NATS_URL"
 	NATS_GLOBAL_ENABLED     = "NATS_GLOBAL_ENABLED"
 	NATS_GLOBAL_EVENTS      = "NATS_GLOBAL_EVENTS"
+	NATS_SPECIFIC_EVENTS    = "NATS_SPECIFIC_EVENTS"
+	NATS_SUBJECT_PREFIX     = "NATS_SUBJECT_PREFIX"
+	NATS_SUBJECT_TEMPLATE   = "NATS_SUBJECT_TEMPLATE"
+	NATS_JETSTREAM_ENABLED  = "NATS_JETSTREAM_ENABLED"
+	NATS_JETSTREAM_STREAM   = "NATS_JETSTREAM_STREAM"
+	NATS_JETSTREAM_SUBJECTS = "NATS_JETSTREAM_SUBJECTS"
+	NATS_JETSTREAM_REPLICAS = "NATS_JETSTREAM_REPLICAS"
+	NATS_JETSTREAM_MAX_AGE  = "NATS_JETSTREAM_MAX_AGE"
+	NATS_JETSTREAM_RETRIES  = "NATS_JETSTREAM_RETRIES"
+	NATS_DEDICATED_STREAM   = "NATS_JETSTREAM_PROVISION_DEDICATED"
+	KAFKA_BROKERS           = "KAFKA_BROKERS"
+	KAFKA_GLOBAL_ENABLED    = "KAFKA_GLOBAL_ENABLED"
+	KAFKA_GLOBAL_EVENTS     = "KAFKA_GLOBAL_EVENTS"
+	KAFKA_ACKS              = "KAFKA_ACKS"
+	KAFKA_COMPRESSION       = "KAFKA_COMPRESSION"
+	REDIS_URL               = "REDIS_URL"
+	REDIS_STREAM_PREFIX     = "REDIS_STREAM_PREFIX"
+	REDIS_STREAM_MAXLEN     = "REDIS_STREAM_MAXLEN"
+	REDIS_GLOBAL_ENABLED    = "REDIS_GLOBAL_ENABLED"
+	REDIS_GLOBAL_EVENTS     = "REDIS_GLOBAL_EVENTS"
+	MQTT_BROKER             = "MQTT_BROKER"
+	MQTT_USERNAME           = "MQTT_USERNAME"
+	MQTT_PASSWORD           = "MQTT_PASSWORD"
+	MQTT_CLIENT_ID          = "MQTT_CLIENT_ID"
+	MQTT_QOS                = "MQTT_QOS"
+	MQTT_TOPIC_PREFIX       = "MQTT_TOPIC_PREFIX"
+	MQTT_TLS_CA_FILE        = "MQTT_TLS_CA_FILE"
+	MQTT_TLS_INSECURE       = "MQTT_TLS_INSECURE"
+	MQTT_GLOBAL_ENABLED     = "MQTT_GLOBAL_ENABLED"
+	MQTT_GLOBAL_EVENTS      = "MQTT_GLOBAL_EVENTS"
+	SSE_ENABLED             = "SSE_ENABLED"
+	SSE_BUFFER_SIZE         = "SSE_BUFFER_SIZE"
+	EVENT_FORMAT            = "EVENT_FORMAT"
+	CLOUDEVENTS_HTTP_MODE   = "CLOUDEVENTS_HTTP_MODE"
+	EVENT_STORE_ENABLED     = "EVENT_STORE_ENABLED"
+	EVENT_STORE_RETENTION   = "EVENT_STORE_RETENTION_HOURS"
 	EVENT_IGNORE_GROUP      = "EVENT_IGNORE_GROUP"
 	EVENT_IGNORE_STATUS     = "EVENT_IGNORE_STATUS"
 	QRCODE_MAX_COUNT        = "QRCODE_MAX_COUNT"
@@ -0,0 +1,198 @@
+package event_store_handler
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/EvolutionAPI/evolution-go/pkg/config"
+	event_store_repository "github.com/EvolutionAPI/evolution-go/pkg/eventStore/repository"
+	event_store_service "github.com/EvolutionAPI/evolution-go/pkg/eventStore/service"
+	instance_model "github.com/EvolutionAPI/evolution-go/pkg/instance/model"
+	"github.com/gin-gonic/gin"
+)
+
+type EventStoreHandler interface {
+	ListEvents(ctx *gin.Context)
+	ReplayEvents(ctx *gin.Context)
+}
+
+type eventStoreHandler struct {
+	eventStoreService event_store_service.EventStoreService
+	config            *config.Config
+}
+
+type ListEventsQuery struct {
+	Event     string `form:"event"`
+	StartDate string `form:"start_date"`
+	EndDate   string `form:"end_date"`
+	Limit     int    `form:"limit"`
+	Offset    int    `form:"offset"`
+}
+
+type ReplayEventsStruct struct {
+	Event     string `json:"event"`
+	StartDate string `json:"startDate"`
+	EndDate   string `json:"endDate"`
+	Limit     int    `json:"limit"`
+}
+
+// List stored events
+// @Summary List stored events
+// @Description List the events dispatched by the instance and kept in the event store, in chronological order
+// @Tags Events
+// @Produce json
+// @Param event query string false "Event type (e.g. message, receipt)"
+// @Param start_date query string false "Start of the time range (RFC3339 or YYYY-MM-DD)"
+// @Param end_date query string false "End of the time range (RFC3339 or YYYY-MM-DD)"
+// @Param limit query int false "Max records (default 100, max 1000)"
+// @Param offset query int false "Records to skip"
+// @Success 200 {object} gin.H "success"
+// @Failure 400 {object} gin.H "Error on validation"
+// @Failure 500 {object} gin.H "Internal server error"
+// @Router /events [get]
+func (e *eventStoreHandler) ListEvents(ctx *gin.Context) {
+	instance, ok := e.getInstance(ctx)
+	if !ok {
+		return
+	}
+
+	var query ListEventsQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	filter, err := buildFilter(query.Event, query.StartDate, query.EndDate)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	filter.Limit = query.Limit
+	if filter.Limit <= 0 {
+		filter.Limit = 100 // Default: 100 registros
+	}
+	if filter.Limit > 1000 {
+		filter.Limit = 1000
+	}
+	filter.Offset = max(query.Offset, 0)
+
+	events, err := e.eventStoreService.List(instance.Id, filter)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "success", "data": events})
+}
+
+// Replay stored events
+// @Summary Replay stored events
+// @Description Send the stored events of a time range again, in chronological order, to the current webhook and broker targets of the instance
+// @Tags Events
+// @Accept json
+// @Produce json
+// @Param replay body ReplayEventsStruct true "Time range and optional event type"
+// @Success 200 {object} gin.H "success"
+// @Failure 400 {object} gin.H "Error on validation"
+// @Failure 500 {object} gin.H "Internal server error"
+// @Router /events/replay [post]
+func (e *eventStoreHandler) ReplayEvents(ctx *gin.Context) {
+	instance, ok := e.getInstance(ctx)
+	if !ok {
+		return
+	}
+
+	var data *ReplayEventsStruct
+	if err := ctx.ShouldBindJSON(&data); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// O intervalo é obrigatório para que um reenvio não percorra todo o histórico por engano
+	if data.StartDate == "" || data.EndDate == "" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "startDate and endDate are required"})
+		return
+	}
+
+	filter, err := buildFilter(data.Event, data.StartDate, data.EndDate)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	filter.Limit = data.Limit
+	if filter.Limit <= 0 {
+		filter.Limit = 1000 // Default: 1000 eventos
+	}
+	if filter.Limit > 10000 {
+		filter.Limit = 10000
+	}
+
+	replayed, err := e.eventStoreService.Replay(instance, filter)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error(), "data": gin.H{"replayed": replayed}})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "success", "data": gin.H{"replayed": replayed}})
+}
+
+func (e *eventStoreHandler) getInstance(ctx *gin.Context) (*instance_model.Instance, bool) {
+	if !e.config.EventStoreEnabled {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "event store is disabled, set EVENT_STORE_ENABLED=true"})
+		return nil, false
+	}
+
+	instance, ok := ctx.MustGet("instance").(*instance_model.Instance)
+	if !ok {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "instance not found"})
+		return nil, false
+	}
+
+	return instance, true
+}
+
+func buildFilter(event string, startDate string, endDate string) (event_store_repository.EventFilter, error) {
+	filter := event_store_repository.EventFilter{Event: strings.ToLower(event)}
+
+	var err error
+	if startDate != "" {
+		filter.StartDate, err = parseDate(startDate, false)
+		if err != nil {
+			return filter, errors.New("invalid start date, use RFC3339 or YYYY-MM-DD")
+		}
+	}
+	if endDate != "" {
+		filter.EndDate, err = parseDate(endDate, true)
+		if err != nil {
+			return filter, errors.New("invalid end date, use RFC3339 or YYYY-MM-DD")
+		}
+	}
+
+	return filter, nil
+}
+
+// parseDate aceita RFC3339 ou apenas a data; no fim do intervalo a data simples cobre o dia inteiro
+func parseDate(value string, endOfDay bool) (time.Time, error) {
+	if parsed, err := time.Parse(time.RFC3339, value); err == nil {
+		return parsed, nil
+	}
+
+	parsed, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	if endOfDay {
+		parsed = parsed.Add(24*time.Hour - time.Nanosecond)
+	}
+
+	return parsed, nil
+}
+
+func NewEventStoreHandler(eventStoreService event_store_service.EventStoreService, config *config.Config) EventStoreHandler {
+	return &eventStoreHandler{eventStoreService: eventStoreService, config: config}
+}
@@ -0,0 +1,67 @@
+package event_store_model
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// StoredEvent é um evento despachado pela instância, guardado para consulta e reenvio após falhas dos destinos
+type StoredEvent struct {
+	Id         string          `json:"id" gorm:"type:uuid;primaryKey"`
+	InstanceId string          `json:"instanceId" gorm:"type:uuid;index:idx_stored_events_instance_created"`
+	Event      string          `json:"event" gorm:"index"`
+	Payload    json.RawMessage `json:"payload" gorm:"type:bytea"`
+	CreatedAt  time.Time       `json:"createdAt" gorm:"index:idx_stored_events_instance_created"`
+}
+
+func (m *StoredEvent) BeforeCreate(tx *gorm.DB) (err error) {
+	if m.Id == "" {
+		m.Id = uuid.New().String()
+	}
+	return
+}
+
+// NewStoredEvent monta o registro a partir do payload do myEventHandler. O instanceToken não é
+// persistido: no reenvio ele é preenchido com o token atual da instância
+func NewStoredEvent(instanceId string, event string, payload []byte, createdAt time.Time) (*StoredEvent, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(payload, &fields); err != nil {
+		return nil, err
+	}
+	delete(fields, "instanceToken")
+
+	stored, err := json.Marshal(fields)
+	if err != nil {
+		return nil, err
+	}
+
+	return &StoredEvent{
+		InstanceId: instanceId,
+		Event:      strings.ToLower(event),
+		Payload:    stored,
+		CreatedAt:  createdAt,
+	}, nil
+}
+
+// ReplayPayload devolve o payload original com o token atual da instância e a marcação replayed,
+// que permite ao destino distinguir o reenvio da entrega original
+func (m *StoredEvent) ReplayPayload(instanceToken string) ([]byte, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(m.Payload, &fields); err != nil {
+		return nil, err
+	}
+
+	token, err := json.Marshal(instanceToken)
+	if err != nil {
+		return nil, err
+	}
+
+	fields["instanceToken"] = token
+	fields["replayed"] = json.RawMessage("true")
+
+	return json.Marshal(fields)
+}
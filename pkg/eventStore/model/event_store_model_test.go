@@ -0,0 +1,79 @@
+package event_store_model
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestStoredEventPayload(t *testing.T) {
+	tests := []struct {
+		name      string
+		event     string
+		payload   string
+		token     string
+		wantEvent string
+		stored    string
+		replay    string
+	}{
+		{
+			name:      "token is not stored and is refreshed on replay",
+			event:     "Message",
+			payload:   `{"event":"Message","instanceId":"inst","instanceName":"vendas","instanceToken":"old","data":{"Info":{"ID":"ABC"}}}`,
+			token:     "new",
+			wantEvent: "message",
+			stored:    `{"event":"Message","instanceId":"inst","instanceName":"vendas","data":{"Info":{"ID":"ABC"}}}`,
+			replay:    `{"event":"Message","instanceId":"inst","instanceName":"vendas","instanceToken":"new","replayed":true,"data":{"Info":{"ID":"ABC"}}}`,
+		},
+		{
+			name:      "large numbers keep their precision",
+			event:     "Receipt",
+			payload:   `{"event":"Receipt","instanceId":"inst","data":{"FileLength":9007199254740993}}`,
+			token:     "tk",
+			wantEvent: "receipt",
+			stored:    `{"event":"Receipt","instanceId":"inst","data":{"FileLength":9007199254740993}}`,
+			replay:    `{"event":"Receipt","instanceId":"inst","instanceToken":"tk","replayed":true,"data":{"FileLength":9007199254740993}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stored, err := NewStoredEvent("inst", tt.event, []byte(tt.payload), time.Now())
+			if err != nil {
+				t.Fatalf("NewStoredEvent() error = %v", err)
+			}
+
+			if stored.Event != tt.wantEvent {
+				t.Errorf("Event = %q, want %q", stored.Event, tt.wantEvent)
+			}
+			assertJSON(t, stored.Payload, tt.stored)
+
+			replay, err := stored.ReplayPayload(tt.token)
+			if err != nil {
+				t.Fatalf("ReplayPayload() error = %v", err)
+			}
+			assertJSON(t, replay, tt.replay)
+		})
+	}
+}
+
+func assertJSON(t *testing.T, got []byte, want string) {
+	t.Helper()
+
+	var gotValue, wantValue interface{}
+	decode := func(data []byte, value *interface{}) {
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.UseNumber()
+		if err := decoder.Decode(value); err != nil {
+			t.Fatalf("invalid JSON %s: %v", data, err)
+		}
+	}
+	decode(got, &gotValue)
+	decode([]byte(want), &wantValue)
+
+	if !reflect.DeepEqual(gotValue, wantValue) {
+		t.Errorf("payload = %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,68 @@
+package event_store_repository
+
+import (
+	"time"
+
+	event_store_model "github.com/EvolutionAPI/evolution-go/pkg/eventStore/model"
+	"gorm.io/gorm"
+)
+
+// EventFilter define os filtros opcionais da consulta e do reenvio de eventos
+type EventFilter struct {
+	Event     string
+	StartDate time.Time
+	EndDate   time.Time
+	Limit     int
+	Offset    int
+}
+
+type EventStoreRepository interface {
+	Insert(event *event_store_model.StoredEvent) error
+	List(instanceId string, filter EventFilter) ([]event_store_model.StoredEvent, error)
+	DeleteOlderThan(before time.Time) (int64, error)
+}
+
+type eventStoreRepository struct {
+	db *gorm.DB
+}
+
+func (e *eventStoreRepository) Insert(event *event_store_model.StoredEvent) error {
+	return e.db.Create(event).Error
+}
+
+// List retorna os eventos em ordem cronológica, a mesma usada no reenvio
+func (e *eventStoreRepository) List(instanceId string, filter EventFilter) ([]event_store_model.StoredEvent, error) {
+	query := e.db.Where("instance_id = ?", instanceId)
+
+	if filter.Event != "" {
+		query = query.Where("event = ?", filter.Event)
+	}
+	if !filter.StartDate.IsZero() {
+		query = query.Where("created_at >= ?", filter.StartDate)
+	}
+	if !filter.EndDate.IsZero() {
+		query = query.Where("created_at <= ?", filter.EndDate)
+	}
+
+	var events []event_store_model.StoredEvent
+	err := query.
+		Order("created_at ASC").
+		Order("id ASC").
+		Limit(filter.Limit).
+		Offset(filter.Offset).
+		Find(&events).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
+
+func (e *eventStoreRepository) DeleteOlderThan(before time.Time) (int64, error) {
+	result := e.db.Where("created_at < ?", before).Delete(&event_store_model.StoredEvent{})
+	return result.RowsAffected, result.Error
+}
+
+func NewEventStoreRepository(db *gorm.DB) EventStoreRepository {
+	return &eventStoreRepository{db: db}
+}
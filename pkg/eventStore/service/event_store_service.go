@@ -0,0 +1,112 @@
+package event_store_service
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/EvolutionAPI/evolution-go/pkg/config"
+	event_store_model "github.com/EvolutionAPI/evolution-go/pkg/eventStore/model"
+	event_store_repository "github.com/EvolutionAPI/evolution-go/pkg/eventStore/repository"
+	instance_model "github.com/EvolutionAPI/evolution-go/pkg/instance/model"
+	logger_wrapper "github.com/EvolutionAPI/evolution-go/pkg/logger"
+	whatsmeow_service "github.com/EvolutionAPI/evolution-go/pkg/whatsmeow/service"
+	"github.com/gomessguii/logger"
+)
+
+const (
+	replayBatchSize   = 500
+	retentionInterval = time.Hour
+)
+
+type EventStoreService interface {
+	List(instanceId string, filter event_store_repository.EventFilter) ([]event_store_model.StoredEvent, error)
+	Replay(instance *instance_model.Instance, filter event_store_repository.EventFilter) (int, error)
+}
+
+type eventStoreService struct {
+	eventStoreRepository event_store_repository.EventStoreRepository
+	whatsmeowService     whatsmeow_service.WhatsmeowService
+	config               *config.Config
+	loggerWrapper        *logger_wrapper.LoggerManager
+}
+
+func (e *eventStoreService) List(instanceId string, filter event_store_repository.EventFilter) ([]event_store_model.StoredEvent, error) {
+	return e.eventStoreRepository.List(instanceId, filter)
+}
+
+// Replay reenvia os eventos do intervalo, em ordem cronológica, para os destinos atuais da instância
+// (webhook, webhooks adicionais e brokers; WebSocket, SSE e o status do MQTT ficam de fora), respeitando as inscrições e o eventFormat configurados hoje.
+// filter.Limit limita o total de eventos reenviados
+func (e *eventStoreService) Replay(instance *instance_model.Instance, filter event_store_repository.EventFilter) (int, error) {
+	total := filter.Limit
+	replayed := 0
+
+	for replayed < total {
+		batch := filter
+		batch.Offset = filter.Offset + replayed
+		batch.Limit = min(replayBatchSize, total-replayed)
+
+		events, err := e.eventStoreRepository.List(instance.Id, batch)
+		if err != nil {
+			return replayed, err
+		}
+
+		for _, event := range events {
+			payload, err := event.ReplayPayload(instance.Token)
+			if err != nil {
+				e.loggerWrapper.GetLogger(instance.Id).LogError("[%s] Failed to rebuild stored event %s: %v", instance.Id, event.Id, err)
+				continue
+			}
+
+			queueName := strings.ToLower(fmt.Sprintf("%s.%s", instance.Id, event.Event))
+			e.whatsmeowService.ReplayEvent(instance, queueName, payload)
+		}
+
+		replayed += len(events)
+		if len(events) < batch.Limit {
+			break
+		}
+	}
+
+	e.loggerWrapper.GetLogger(instance.Id).LogInfo("[%s] Replayed %d stored events", instance.Id, replayed)
+
+	return replayed, nil
+}
+
+// runRetention remove periodicamente os eventos mais antigos que EVENT_STORE_RETENTION_HOURS
+func (e *eventStoreService) runRetention() {
+	ticker := time.NewTicker(retentionInterval)
+	defer ticker.Stop()
+
+	for {
+		deleted, err := e.eventStoreRepository.DeleteOlderThan(time.Now().Add(-e.config.EventStoreRetention))
+		if err != nil {
+			logger.LogError("Failed to apply event store retention: %v", err)
+		} else if deleted > 0 {
+			logger.LogInfo("Event store retention removed %d events", deleted)
+		}
+
+		<-ticker.C
+	}
+}
+
+func NewEventStoreService(
+	eventStoreRepository event_store_repository.EventStoreRepository,
+	whatsmeowService whatsmeow_service.WhatsmeowService,
+	config *config.Config,
+	loggerWrapper *logger_wrapper.LoggerManager,
+) EventStoreService {
+	e := &eventStoreService{
+		eventStoreRepository: eventStoreRepository,
+		whatsmeowService:     whatsmeowService,
+		config:               config,
+		loggerWrapper:        loggerWrapper,
+	}
+
+	if config.EventStoreEnabled && config.EventStoreRetention > 0 {
+		go e.runRetention()
+	}
+
+	return e
+}
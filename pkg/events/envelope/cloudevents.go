@@ -0,0 +1,141 @@
+package event_envelope
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const cloudEventsSpecVersion = "1.0"
+
+// CloudEventsBatchContentType é o Content-Type do modo batch do CloudEvents, um array de eventos estruturados
+const CloudEventsBatchContentType = "application/cloudevents-batch+json"
+
+// CloudEvent é o evento no modo estruturado do CloudEvents 1.0, com data no formato original do evento
+type CloudEvent struct {
+	SpecVersion     string          `json:"specversion"`
+	Id              string          `json:"id"`
+	Source          string          `json:"source"`
+	Type            string          `json:"type"`
+	Subject         string          `json:"subject,omitempty"`
+	Time            time.Time       `json:"time"`
+	DataContentType string          `json:"datacontenttype"`
+	Data            json.RawMessage `json:"data"`
+	InstanceName    string          `json:"instancename,omitempty"` // extensão
+}
+
+// Format aplica o formato configurado ao payload montado pelo myEventHandler; raw ou vazio mantém o payload
+func Format(format string, payload []byte) ([]byte, error) {
+	switch format {
+	case FormatV1:
+		return Build(payload)
+	case FormatCloudEvents:
+		return BuildCloudEvent(payload)
+	}
+	return payload, nil
+}
+
+// BuildCloudEvent converte o payload para CloudEvents: event vira type (evolution.<evento>), a instância
+// vira source (/instances/<id>) e o id da mensagem do WhatsApp, quando existe, vira id
+func BuildCloudEvent(payload []byte) ([]byte, error) {
+	return buildCloudEvent(payload, time.Now().UTC())
+}
+
+func buildCloudEvent(payload []byte, timestamp time.Time) ([]byte, error) {
+	var raw struct {
+		Event        string          `json:"event"`
+		InstanceId   string          `json:"instanceId"`
+		InstanceName string          `json:"instanceName"`
+		Data         json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(payload, &raw); err != nil {
+		return nil, err
+	}
+
+	data := raw.Data
+	if len(data) == 0 {
+		data = json.RawMessage("{}")
+	}
+
+	var keys struct {
+		Info struct {
+			ID   string
+			Chat string
+		}
+		Chat string
+		JID  string
+	}
+	_ = json.Unmarshal(data, &keys)
+
+	id := keys.Info.ID
+	if id == "" {
+		id = uuid.New().String()
+	}
+
+	subject := keys.Info.Chat
+	if subject == "" {
+		subject = keys.Chat
+	}
+	if subject == "" {
+		subject = keys.JID
+	}
+
+	return json.Marshal(CloudEvent{
+		SpecVersion:     cloudEventsSpecVersion,
+		Id:              id,
+		Source:          "/instances/" + raw.InstanceId,
+		Type:            "evolution." + strings.ToLower(raw.Event),
+		Subject:         subject,
+		Time:            timestamp,
+		DataContentType: "application/json",
+		Data:            data,
+		InstanceName:    raw.InstanceName,
+	})
+}
+
+// CloudEventBinary converte um CloudEvent estruturado para o modo binário do HTTP: os atributos vão
+// para headers ce-* e o corpo passa a ser apenas data. Retorna false se o payload não for um CloudEvent
+func CloudEventBinary(payload []byte) (map[string]string, []byte, bool) {
+	var attributes map[string]json.RawMessage
+	if err := json.Unmarshal(payload, &attributes); err != nil {
+		return nil, nil, false
+	}
+	if _, ok := attributes["specversion"]; !ok {
+		return nil, nil, false
+	}
+
+	headers := make(map[string]string)
+	for name, value := range attributes {
+		switch name {
+		case "data":
+			continue
+		case "datacontenttype":
+			headers["Content-Type"] = unquote(value)
+		default:
+			headers["ce-"+name] = unquote(value)
+		}
+	}
+
+	return headers, attributes["data"], true
+}
+
+// IsCloudEvent indica se o payload é um CloudEvent no modo estruturado
+func IsCloudEvent(payload []byte) bool {
+	var attributes map[string]json.RawMessage
+	if err := json.Unmarshal(payload, &attributes); err != nil {
+		return false
+	}
+
+	_, ok := attributes["specversion"]
+	return ok
+}
+
+func unquote(value json.RawMessage) string {
+	var s string
+	if err := json.Unmarshal(value, &s); err == nil {
+		return s
+	}
+	return string(value)
+}
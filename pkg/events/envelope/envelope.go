@@ -0,0 +1,120 @@
+package event_envelope
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Formatos de payload aceitos em eventFormat no /instance/connect e em EVENT_FORMAT
+const (
+	FormatRaw         = "raw"
+	FormatV1          = "v1"
+	FormatCloudEvents = "cloudevents"
+)
+
+const Version = "v1"
+
+// Envelope é o payload estável entregue às instâncias com eventFormat v1. Data contém o DTO do evento
+type Envelope struct {
+	Event      string      `json:"event"`
+	Version    string      `json:"version"`
+	InstanceId string      `json:"instanceId"`
+	Timestamp  time.Time   `json:"timestamp"`
+	Data       interface{} `json:"data"`
+}
+
+// mapping associa um evento ao seu DTO (usado também na geração do schema) e à função de conversão.
+// Todo evento do registry precisa de um mapping; o teste TestMappings garante a cobertura
+type mapping struct {
+	dto     interface{}
+	convert func(event string, data json.RawMessage) (interface{}, error)
+}
+
+var mappings = map[string]mapping{
+	"Message":                 {MessageData{}, messageData},
+	"SendMessage":             {MessageData{}, messageData},
+	"HistorySync":             {HistorySyncData{}, historySyncData},
+	"OfflineSyncCompleted":    {OfflineSyncData{}, offlineSyncData},
+	"Receipt":                 {ReceiptData{}, receiptData},
+	"Presence":                {PresenceData{}, presenceData},
+	"ChatPresence":            {ChatPresenceData{}, chatPresenceData},
+	"Archive":                 {ArchiveData{}, archiveData},
+	"Connected":               {ConnectionData{}, connectionData},
+	"PairSuccess":             {ConnectionData{}, connectionData},
+	"LoggedOut":               {ConnectionData{}, connectionData},
+	"Disconnected":            {ConnectionData{}, connectionData},
+	"ConnectFailure":          {ConnectionData{}, connectionData},
+	"TemporaryBan":            {ConnectionData{}, connectionData},
+	"QRCode":                  {QrCodeData{}, qrCodeData},
+	"QRTimeout":               {QrCodeData{}, qrCodeData},
+	"CallOffer":               {CallData{}, callData},
+	"CallAccept":              {CallData{}, callData},
+	"CallTerminate":           {CallData{}, callData},
+	"CallOfferNotice":         {CallData{}, callData},
+	"CallRelayLatency":        {CallData{}, callData},
+	"LabelEdit":               {LabelData{}, labelData},
+	"LabelAssociationChat":    {LabelData{}, labelData},
+	"LabelAssociationMessage": {LabelData{}, labelData},
+	"Contact":                 {ContactData{}, contactData},
+	"PushName":                {ContactData{}, contactData},
+	"GroupInfo":               {GroupData{}, groupData},
+	"JoinedGroup":             {GroupData{}, groupData},
+	"NewsletterJoin":          {NewsletterData{}, newsletterData},
+	"NewsletterLeave":         {NewsletterData{}, newsletterData},
+	"Picture":                 {PictureData{}, pictureData},
+	"UserAbout":               {UserAboutData{}, userAboutData},
+	"IdentityChange":          {IdentityChangeData{}, identityChangeData},
+	"Blocklist":               {BlocklistData{}, blocklistData},
+	"Mute":                    {MuteData{}, muteData},
+	"Pin":                     {PinData{}, pinData},
+	"Star":                    {StarData{}, starData},
+	"DeleteForMe":             {DeleteForMeData{}, deleteForMeData},
+	"MarkChatAsRead":          {MarkChatAsReadData{}, markChatAsReadData},
+}
+
+// IsFormat valida o eventFormat recebido; vazio usa o formato padrão (EVENT_FORMAT)
+func IsFormat(format string) bool {
+	return format == "" || format == FormatRaw || format == FormatV1 || format == FormatCloudEvents
+}
+
+// Build converte o payload montado pelo myEventHandler ({event, data, instanceId, ...}) para o envelope v1
+func Build(payload []byte) ([]byte, error) {
+	return build(payload, time.Now().UTC())
+}
+
+func build(payload []byte, timestamp time.Time) ([]byte, error) {
+	var raw struct {
+		Event      string          `json:"event"`
+		InstanceId string          `json:"instanceId"`
+		Data       json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(payload, &raw); err != nil {
+		return nil, err
+	}
+
+	envelope := Envelope{
+		Event:      raw.Event,
+		Version:    Version,
+		InstanceId: raw.InstanceId,
+		Timestamp:  timestamp,
+	}
+
+	// O v1 nunca repassa a estrutura do whatsmeow: um evento sem DTO é um erro
+	m, ok := mappings[raw.Event]
+	if !ok {
+		return nil, fmt.Errorf("event %s has no v1 mapping", raw.Event)
+	}
+
+	if len(raw.Data) == 0 || string(raw.Data) == "null" {
+		envelope.Data = map[string]interface{}{}
+	} else {
+		data, err := m.convert(raw.Event, raw.Data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert %s event: %w", raw.Event, err)
+		}
+		envelope.Data = data
+	}
+
+	return json.Marshal(envelope)
+}
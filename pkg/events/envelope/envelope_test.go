@@ -0,0 +1,235 @@
+package event_envelope
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/EvolutionAPI/evolution-go/pkg/internal/event_types"
+)
+
+func TestBuild(t *testing.T) {
+	now := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		payload string
+		want    string
+	}{
+		{
+			name: "text message with quote",
+			payload: `{"event":"Message","instanceId":"inst","instanceToken":"secret","data":{
+				"Info":{"ID":"ABC","Chat":"5511@s.whatsapp.net","Sender":"5511@s.whatsapp.net","IsFromMe":false,"IsGroup":false,"PushName":"Ana","Timestamp":"2025-01-01T00:00:00Z"},
+				"Message":{"extendedTextMessage":{"text":"oi"}},
+				"quoted":{"stanzaID":"XYZ","quotedMessage":{"conversation":"olá"}},"isQuoted":true}}`,
+			want: `{"event":"Message","version":"v1","instanceId":"inst","timestamp":"2025-01-02T03:04:05Z","data":{
+				"id":"ABC","chat":"5511@s.whatsapp.net","sender":"5511@s.whatsapp.net","fromMe":false,"isGroup":false,"pushName":"Ana",
+				"timestamp":"2025-01-01T00:00:00Z","type":"text","text":"oi","quoted":{"id":"XYZ","type":"text","text":"olá"},"edited":false,"revoked":false}}`,
+		},
+		{
+			name: "image message with media url",
+			payload: `{"event":"Message","instanceId":"inst","data":{
+				"Info":{"ID":"IMG","Chat":"1@g.us","Sender":"2@s.whatsapp.net","IsGroup":true,"Timestamp":"2025-01-01T00:00:00Z"},
+				"Message":{"imageMessage":{"mimetype":"image/jpeg","caption":"foto","fileLength":10},"mediaUrl":"https://s3/img.jpg"}}}`,
+			want: `{"event":"Message","version":"v1","instanceId":"inst","timestamp":"2025-01-02T03:04:05Z","data":{
+				"id":"IMG","chat":"1@g.us","sender":"2@s.whatsapp.net","fromMe":false,"isGroup":true,"timestamp":"2025-01-01T00:00:00Z",
+				"type":"image","media":{"url":"https://s3/img.jpg","mimetype":"image/jpeg","caption":"foto","fileLength":10},"edited":false,"revoked":false}}`,
+		},
+		{
+			name:    "delivered receipt",
+			payload: `{"event":"Receipt","instanceId":"inst","data":{"Chat":"5511@s.whatsapp.net","Sender":"5511@s.whatsapp.net","MessageIDs":["A","B"],"Type":"","Timestamp":"2025-01-01T00:00:00Z"}}`,
+			want: `{"event":"Receipt","version":"v1","instanceId":"inst","timestamp":"2025-01-02T03:04:05Z","data":{
+				"messageIds":["A","B"],"chat":"5511@s.whatsapp.net","sender":"5511@s.whatsapp.net","isGroup":false,"type":"delivered","timestamp":"2025-01-01T00:00:00Z"}}`,
+		},
+		{
+			name:    "connect failure keeps evolution reason",
+			payload: `{"event":"ConnectFailure","instanceId":"inst","data":{"Reason":401,"Message":"","reason":"logged out"}}`,
+			want:    `{"event":"ConnectFailure","version":"v1","instanceId":"inst","timestamp":"2025-01-02T03:04:05Z","data":{"status":"failed","reason":"logged out"}}`,
+		},
+		{
+			name:    "temporary ban expiration in seconds",
+			payload: `{"event":"TemporaryBan","instanceId":"inst","data":{"Code":101,"Expire":3600000000000,"reason":"spam","expire":3600000000000}}`,
+			want:    `{"event":"TemporaryBan","version":"v1","instanceId":"inst","timestamp":"2025-01-02T03:04:05Z","data":{"status":"banned","reason":"spam","expiresIn":3600}}`,
+		},
+		{
+			name:    "qr timeout",
+			payload: `{"event":"QRTimeout","instanceId":"inst","data":{"reason":"limit","qrcount":5,"maxCount":5,"forceLogout":true}}`,
+			want:    `{"event":"QRTimeout","version":"v1","instanceId":"inst","timestamp":"2025-01-02T03:04:05Z","data":{"count":5,"maxCount":5,"reason":"limit"}}`,
+		},
+		{
+			name:    "group name change",
+			payload: `{"event":"GroupInfo","instanceId":"inst","data":{"JID":"1@g.us","Sender":"2@s.whatsapp.net","Timestamp":"2025-01-01T00:00:00Z","Name":{"Name":"Equipe"},"Join":["3@s.whatsapp.net"]}}`,
+			want: `{"event":"GroupInfo","version":"v1","instanceId":"inst","timestamp":"2025-01-02T03:04:05Z","data":{
+				"jid":"1@g.us","name":"Equipe","sender":"2@s.whatsapp.net","join":["3@s.whatsapp.net"],"timestamp":"2025-01-01T00:00:00Z"}}`,
+		},
+		{
+			name:    "mute with end time",
+			payload: `{"event":"Mute","instanceId":"inst","data":{"JID":"5511@s.whatsapp.net","Timestamp":"2025-01-01T00:00:00Z","Action":{"muted":true,"muteEndTimestamp":1735693200000},"FromFullSync":false}}`,
+			want: `{"event":"Mute","version":"v1","instanceId":"inst","timestamp":"2025-01-02T03:04:05Z","data":{
+				"chat":"5511@s.whatsapp.net","muted":true,"mutedUntil":"2025-01-01T01:00:00Z","timestamp":"2025-01-01T00:00:00Z"}}`,
+		},
+		{
+			name:    "star from another device",
+			payload: `{"event":"Star","instanceId":"inst","data":{"ChatJID":"1@g.us","SenderJID":"2@s.whatsapp.net","IsFromMe":false,"MessageID":"ABC","Timestamp":"2025-01-01T00:00:00Z","Action":{"starred":true}}}`,
+			want: `{"event":"Star","version":"v1","instanceId":"inst","timestamp":"2025-01-02T03:04:05Z","data":{
+				"chat":"1@g.us","sender":"2@s.whatsapp.net","messageId":"ABC","fromMe":false,"starred":true,"timestamp":"2025-01-01T00:00:00Z"}}`,
+		},
+		{
+			name:    "blocklist changes",
+			payload: `{"event":"Blocklist","instanceId":"inst","data":{"Action":"","DHash":"a","PrevDHash":"b","Changes":[{"JID":"5511@s.whatsapp.net","Action":"block"}]}}`,
+			want: `{"event":"Blocklist","version":"v1","instanceId":"inst","timestamp":"2025-01-02T03:04:05Z","data":{
+				"changes":[{"jid":"5511@s.whatsapp.net","action":"block"}]}}`,
+		},
+		{
+			name: "sent message quotes from context info",
+			payload: `{"event":"SendMessage","instanceId":"inst","data":{
+				"Info":{"ID":"OUT","Chat":"5511@s.whatsapp.net","Sender":"5522@s.whatsapp.net","IsFromMe":true,"Timestamp":"2025-01-01T00:00:00Z"},
+				"Message":{"conversation":"resposta"},
+				"MessageContextInfo":{"stanzaID":"IN","participant":"5511@s.whatsapp.net","quotedMessage":{"conversation":""}}}}`,
+			want: `{"event":"SendMessage","version":"v1","instanceId":"inst","timestamp":"2025-01-02T03:04:05Z","data":{
+				"id":"OUT","chat":"5511@s.whatsapp.net","sender":"5522@s.whatsapp.net","fromMe":true,"isGroup":false,"timestamp":"2025-01-01T00:00:00Z",
+				"type":"text","text":"resposta","quoted":{"id":"IN","type":"text"},"edited":false,"revoked":false}}`,
+		},
+		{
+			name: "history sync conversations",
+			payload: `{"event":"HistorySync","instanceId":"inst","data":{"Data":{"syncType":3,"chunkOrder":2,"progress":40,"conversations":[
+				{"ID":"1@g.us","name":"Equipe","unreadCount":1,"messages":[{"message":{"key":{"remoteJID":"1@g.us","fromMe":false,"ID":"H1","participant":"2@s.whatsapp.net"},
+				"message":{"conversation":"bom dia"},"messageTimestamp":1735689600,"pushName":"Ana"}}]}]}}}`,
+			want: `{"event":"HistorySync","version":"v1","instanceId":"inst","timestamp":"2025-01-02T03:04:05Z","data":{
+				"syncType":"recent","chunkOrder":2,"progress":40,"conversations":[{"jid":"1@g.us","name":"Equipe","unreadCount":1,"messages":[
+				{"id":"H1","chat":"1@g.us","sender":"2@s.whatsapp.net","fromMe":false,"isGroup":true,"pushName":"Ana","timestamp":"2025-01-01T00:00:00Z",
+				"type":"text","text":"bom dia","edited":false,"revoked":false}]}]}}`,
+		},
+		{
+			name:    "offline sync completed",
+			payload: `{"event":"OfflineSyncCompleted","instanceId":"inst","data":{"Count":12}}`,
+			want:    `{"event":"OfflineSyncCompleted","version":"v1","instanceId":"inst","timestamp":"2025-01-02T03:04:05Z","data":{"count":12}}`,
+		},
+		{
+			name:    "missing data",
+			payload: `{"event":"Disconnected","instanceId":"inst"}`,
+			want:    `{"event":"Disconnected","version":"v1","instanceId":"inst","timestamp":"2025-01-02T03:04:05Z","data":{}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := build([]byte(tt.payload), now)
+			if err != nil {
+				t.Fatalf("build() error = %v", err)
+			}
+
+			var gotValue, wantValue interface{}
+			if err := json.Unmarshal(got, &gotValue); err != nil {
+				t.Fatalf("invalid output: %v", err)
+			}
+			if err := json.Unmarshal([]byte(tt.want), &wantValue); err != nil {
+				t.Fatalf("invalid expectation: %v", err)
+			}
+
+			if !reflect.DeepEqual(gotValue, wantValue) {
+				t.Errorf("build() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildUnknownEvent(t *testing.T) {
+	if _, err := build([]byte(`{"event":"Unknown","instanceId":"inst","data":{"Field":1}}`), time.Now()); err == nil {
+		t.Error("build() should fail for events without a v1 mapping")
+	}
+}
+
+func TestMappings(t *testing.T) {
+	schemas := Schemas()
+
+	for _, event := range event_types.Events() {
+		if _, ok := mappings[event.Name]; !ok {
+			t.Errorf("event %s has no v1 mapping", event.Name)
+		}
+		if _, ok := schemas[event.Name]; !ok {
+			t.Errorf("event %s has no v1 schema", event.Name)
+		}
+	}
+}
+
+func TestSchemas(t *testing.T) {
+	schemas := Schemas()
+
+	message, ok := schemas["Message"].(map[string]interface{})
+	if !ok {
+		t.Fatal("missing Message schema")
+	}
+
+	data := message["properties"].(map[string]interface{})["data"].(map[string]interface{})
+	required := data["required"].([]string)
+
+	want := []string{"id", "chat", "sender", "fromMe", "isGroup", "timestamp", "type", "edited", "revoked"}
+	if !reflect.DeepEqual(required, want) {
+		t.Errorf("required = %v, want %v", required, want)
+	}
+
+	timestamp := data["properties"].(map[string]interface{})["timestamp"].(map[string]interface{})
+	if timestamp["format"] != "date-time" {
+		t.Errorf("timestamp schema = %v, want date-time string", timestamp)
+	}
+}
+
+func TestBuildCloudEvent(t *testing.T) {
+	now := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	payload := `{"event":"Message","instanceId":"inst","instanceName":"vendas","instanceToken":"secret","data":{"Info":{"ID":"ABC","Chat":"5511@s.whatsapp.net"}}}`
+	got, err := buildCloudEvent([]byte(payload), now)
+	if err != nil {
+		t.Fatalf("buildCloudEvent() error = %v", err)
+	}
+
+	var event CloudEvent
+	if err := json.Unmarshal(got, &event); err != nil {
+		t.Fatalf("invalid output: %v", err)
+	}
+
+	want := CloudEvent{
+		SpecVersion:     "1.0",
+		Id:              "ABC",
+		Source:          "/instances/inst",
+		Type:            "evolution.message",
+		Subject:         "5511@s.whatsapp.net",
+		Time:            now,
+		DataContentType: "application/json",
+		Data:            json.RawMessage(`{"Info":{"ID":"ABC","Chat":"5511@s.whatsapp.net"}}`),
+		InstanceName:    "vendas",
+	}
+	if !reflect.DeepEqual(event, want) {
+		t.Errorf("buildCloudEvent() = %+v, want %+v", event, want)
+	}
+
+	headers, body, ok := CloudEventBinary(got)
+	if !ok {
+		t.Fatal("CloudEventBinary() did not recognize the event")
+	}
+	if string(body) != string(want.Data) {
+		t.Errorf("binary body = %s, want %s", body, want.Data)
+	}
+	for key, value := range map[string]string{
+		"ce-specversion":  "1.0",
+		"ce-id":           "ABC",
+		"ce-type":         "evolution.message",
+		"ce-source":       "/instances/inst",
+		"ce-instancename": "vendas",
+		"Content-Type":    "application/json",
+	} {
+		if headers[key] != value {
+			t.Errorf("header %s = %q, want %q", key, headers[key], value)
+		}
+	}
+
+	if _, _, ok := CloudEventBinary([]byte(payload)); ok {
+		t.Error("CloudEventBinary() should ignore payloads that are not CloudEvents")
+	}
+
+	if !IsCloudEvent(got) || IsCloudEvent([]byte(payload)) {
+		t.Error("IsCloudEvent() should only recognize structured CloudEvents")
+	}
+}
@@ -0,0 +1,929 @@
+package event_envelope
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/proto/waHistorySync"
+	"go.mau.fi/whatsmeow/proto/waWeb"
+)
+
+// MessageData representa uma mensagem recebida ou enviada pela instância (eventos Message e SendMessage)
+type MessageData struct {
+	Id        string      `json:"id"`
+	Chat      string      `json:"chat"`
+	Sender    string      `json:"sender"`
+	SenderAlt string      `json:"senderAlt,omitempty"`
+	FromMe    bool        `json:"fromMe"`
+	IsGroup   bool        `json:"isGroup"`
+	PushName  string      `json:"pushName,omitempty"`
+	Timestamp time.Time   `json:"timestamp"`
+	Type      string      `json:"type"`
+	Text      string      `json:"text,omitempty"`
+	Media     *MediaData  `json:"media,omitempty"`
+	Quoted    *QuotedData `json:"quoted,omitempty"`
+	Edited    bool        `json:"edited"`
+	Revoked   bool        `json:"revoked"`
+}
+
+// MediaData traz a mídia da mensagem; url e base64 só vêm preenchidos com WEBHOOK_FILES habilitado
+type MediaData struct {
+	Url        string `json:"url,omitempty"`
+	Base64     string `json:"base64,omitempty"`
+	Mimetype   string `json:"mimetype,omitempty"`
+	Caption    string `json:"caption,omitempty"`
+	FileName   string `json:"fileName,omitempty"`
+	FileLength uint64 `json:"fileLength,omitempty"`
+}
+
+// QuotedData identifica a mensagem respondida
+type QuotedData struct {
+	Id   string `json:"id"`
+	Type string `json:"type"`
+	Text string `json:"text,omitempty"`
+}
+
+// HistorySyncData resume um lote da sincronização de histórico; as mensagens das conversas seguem o
+// formato do evento Message. syncType é initial_bootstrap, initial_status_v3, full, recent, push_name,
+// non_blocking_data ou on_demand
+type HistorySyncData struct {
+	SyncType      string                    `json:"syncType"`
+	ChunkOrder    uint32                    `json:"chunkOrder"`
+	Progress      uint32                    `json:"progress"`
+	Conversations []HistoryConversationData `json:"conversations"`
+}
+
+type HistoryConversationData struct {
+	Jid         string         `json:"jid"`
+	Name        string         `json:"name,omitempty"`
+	UnreadCount uint32         `json:"unreadCount"`
+	Messages    []*MessageData `json:"messages"`
+}
+
+// OfflineSyncData avisa que os eventos recebidos enquanto a instância estava offline já foram entregues
+type OfflineSyncData struct {
+	Count int `json:"count"`
+}
+
+// ReceiptData representa a confirmação de entrega, leitura ou reprodução de mensagens
+type ReceiptData struct {
+	MessageIds []string  `json:"messageIds"`
+	Chat       string    `json:"chat"`
+	Sender     string    `json:"sender"`
+	IsGroup    bool      `json:"isGroup"`
+	Type       string    `json:"type"`
+	Timestamp  time.Time `json:"timestamp"`
+}
+
+type PresenceData struct {
+	From      string     `json:"from"`
+	Available bool       `json:"available"`
+	LastSeen  *time.Time `json:"lastSeen,omitempty"`
+}
+
+type ChatPresenceData struct {
+	Chat   string `json:"chat"`
+	Sender string `json:"sender"`
+	State  string `json:"state"`
+	Media  string `json:"media,omitempty"`
+}
+
+type ArchiveData struct {
+	Chat      string    `json:"chat"`
+	Archived  bool      `json:"archived"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+type MuteData struct {
+	Chat       string     `json:"chat"`
+	Muted      bool       `json:"muted"`
+	MutedUntil *time.Time `json:"mutedUntil,omitempty"` // ausente com muted indica silenciado para sempre
+	Timestamp  time.Time  `json:"timestamp"`
+}
+
+type PinData struct {
+	Chat      string    `json:"chat"`
+	Pinned    bool      `json:"pinned"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+type MarkChatAsReadData struct {
+	Chat      string    `json:"chat"`
+	Read      bool      `json:"read"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// StarData representa uma mensagem marcada ou desmarcada com estrela em outro aparelho
+type StarData struct {
+	Chat      string    `json:"chat"`
+	Sender    string    `json:"sender,omitempty"`
+	MessageId string    `json:"messageId"`
+	FromMe    bool      `json:"fromMe"`
+	Starred   bool      `json:"starred"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// DeleteForMeData representa uma mensagem apagada apenas para a conta em outro aparelho
+type DeleteForMeData struct {
+	Chat        string    `json:"chat"`
+	Sender      string    `json:"sender,omitempty"`
+	MessageId   string    `json:"messageId"`
+	FromMe      bool      `json:"fromMe"`
+	DeleteMedia bool      `json:"deleteMedia"`
+	Timestamp   time.Time `json:"timestamp"`
+}
+
+// PictureData representa a troca ou remoção da foto de um contato ou grupo
+type PictureData struct {
+	Jid       string    `json:"jid"`
+	Author    string    `json:"author,omitempty"`
+	Removed   bool      `json:"removed"`
+	PictureId string    `json:"pictureId,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+type UserAboutData struct {
+	Jid       string    `json:"jid"`
+	About     string    `json:"about"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// IdentityChangeData avisa que o contato trocou o aparelho principal; implicit indica que a mudança
+// foi detectada por um erro de identidade e não por notificação do servidor
+type IdentityChangeData struct {
+	Jid       string    `json:"jid"`
+	Implicit  bool      `json:"implicit"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// BlocklistData traz as alterações da lista de bloqueio; com action modify a lista mudou por completo
+// e deve ser consultada novamente
+type BlocklistData struct {
+	Action  string                `json:"action,omitempty"`
+	Changes []BlocklistChangeData `json:"changes"`
+}
+
+type BlocklistChangeData struct {
+	Jid    string `json:"jid"`
+	Action string `json:"action"` // block ou unblock
+}
+
+// ConnectionData representa as mudanças de conexão; status é open, close, logged_out, failed ou banned
+type ConnectionData struct {
+	Status    string `json:"status"`
+	Jid       string `json:"jid,omitempty"`
+	PushName  string `json:"pushName,omitempty"`
+	Reason    string `json:"reason,omitempty"`
+	ExpiresIn int64  `json:"expiresIn,omitempty"` // segundos até o fim do banimento temporário
+}
+
+// QrCodeData traz o QR code atual ou, em QRTimeout, o motivo do encerramento
+type QrCodeData struct {
+	Qrcode   string `json:"qrcode,omitempty"`
+	Code     string `json:"code,omitempty"`
+	Count    int    `json:"count"`
+	MaxCount int    `json:"maxCount"`
+	Reason   string `json:"reason,omitempty"`
+}
+
+type CallData struct {
+	CallId    string    `json:"callId"`
+	From      string    `json:"from"`
+	Creator   string    `json:"creator"`
+	Group     string    `json:"group,omitempty"`
+	Reason    string    `json:"reason,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// LabelData cobre a edição de etiquetas e a associação a chats ou mensagens
+type LabelData struct {
+	LabelId   string    `json:"labelId"`
+	Chat      string    `json:"chat,omitempty"`
+	MessageId string    `json:"messageId,omitempty"`
+	Name      string    `json:"name,omitempty"`
+	Color     int32     `json:"color,omitempty"`
+	Deleted   bool      `json:"deleted,omitempty"`
+	Labeled   bool      `json:"labeled,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+type ContactData struct {
+	Jid         string    `json:"jid"`
+	FullName    string    `json:"fullName,omitempty"`
+	FirstName   string    `json:"firstName,omitempty"`
+	PushName    string    `json:"pushName,omitempty"`
+	OldPushName string    `json:"oldPushName,omitempty"`
+	Timestamp   time.Time `json:"timestamp,omitempty"`
+}
+
+// GroupData cobre as alterações de grupo (GroupInfo) e a entrada da instância em um grupo (JoinedGroup)
+type GroupData struct {
+	Jid          string    `json:"jid"`
+	Name         string    `json:"name,omitempty"`
+	Topic        string    `json:"topic,omitempty"`
+	Sender       string    `json:"sender,omitempty"`
+	Participants []string  `json:"participants,omitempty"`
+	Join         []string  `json:"join,omitempty"`
+	Leave        []string  `json:"leave,omitempty"`
+	Promote      []string  `json:"promote,omitempty"`
+	Demote       []string  `json:"demote,omitempty"`
+	Timestamp    time.Time `json:"timestamp,omitempty"`
+}
+
+type NewsletterData struct {
+	Id   string `json:"id"`
+	Name string `json:"name,omitempty"`
+	Role string `json:"role,omitempty"`
+}
+
+func messageData(_ string, data json.RawMessage) (interface{}, error) {
+	var raw struct {
+		Info struct {
+			ID        string
+			Chat      string
+			Sender    string
+			SenderAlt string
+			IsFromMe  bool
+			IsGroup   bool
+			PushName  string
+			Timestamp time.Time
+		}
+		Message *waE2E.Message
+		Quoted  *struct {
+			StanzaID      string         `json:"stanzaID"`
+			QuotedMessage *waE2E.Message `json:"quotedMessage"`
+		} `json:"quoted"`
+		// No SendMessage a mensagem respondida vem no ContextInfo do envio
+		MessageContextInfo *struct {
+			StanzaID      string         `json:"stanzaID"`
+			QuotedMessage *waE2E.Message `json:"quotedMessage"`
+		}
+		Edited  bool `json:"edited"`
+		Revoked bool `json:"revoked"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+
+	// mediaUrl e base64 são acrescentados pelo Evolution dentro de data.Message
+	var extras struct {
+		Message struct {
+			MediaUrl string `json:"mediaUrl"`
+			Base64   string `json:"base64"`
+		}
+	}
+	_ = json.Unmarshal(data, &extras)
+
+	msg := &MessageData{
+		Id:        raw.Info.ID,
+		Chat:      raw.Info.Chat,
+		Sender:    raw.Info.Sender,
+		SenderAlt: raw.Info.SenderAlt,
+		FromMe:    raw.Info.IsFromMe,
+		IsGroup:   raw.Info.IsGroup,
+		PushName:  raw.Info.PushName,
+		Timestamp: raw.Info.Timestamp,
+		Type:      messageType(raw.Message),
+		Text:      messageText(raw.Message),
+		Media:     messageMedia(raw.Message),
+		Edited:    raw.Edited,
+		Revoked:   raw.Revoked,
+	}
+
+	if msg.Media != nil {
+		msg.Media.Url = extras.Message.MediaUrl
+		msg.Media.Base64 = extras.Message.Base64
+	}
+
+	if raw.Quoted == nil {
+		raw.Quoted = raw.MessageContextInfo
+	}
+	if raw.Quoted != nil && raw.Quoted.StanzaID != "" {
+		msg.Quoted = &QuotedData{
+			Id:   raw.Quoted.StanzaID,
+			Type: messageType(raw.Quoted.QuotedMessage),
+			Text: messageText(raw.Quoted.QuotedMessage),
+		}
+	}
+
+	return msg, nil
+}
+
+func historySyncData(_ string, data json.RawMessage) (interface{}, error) {
+	var raw struct {
+		Data *waHistorySync.HistorySync
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+
+	history := &HistorySyncData{
+		SyncType:      strings.ToLower(raw.Data.GetSyncType().String()),
+		ChunkOrder:    raw.Data.GetChunkOrder(),
+		Progress:      raw.Data.GetProgress(),
+		Conversations: []HistoryConversationData{},
+	}
+
+	for _, conversation := range raw.Data.GetConversations() {
+		chat := HistoryConversationData{
+			Jid:         conversation.GetID(),
+			Name:        conversation.GetName(),
+			UnreadCount: conversation.GetUnreadCount(),
+			Messages:    []*MessageData{},
+		}
+		for _, message := range conversation.GetMessages() {
+			if message.GetMessage() != nil {
+				chat.Messages = append(chat.Messages, historyMessage(chat.Jid, message.GetMessage()))
+			}
+		}
+		history.Conversations = append(history.Conversations, chat)
+	}
+
+	return history, nil
+}
+
+// historyMessage converte uma mensagem do histórico, que chega como WebMessageInfo e não como events.Message
+func historyMessage(chat string, info *waWeb.WebMessageInfo) *MessageData {
+	key := info.GetKey()
+	if key.GetRemoteJID() != "" {
+		chat = key.GetRemoteJID()
+	}
+
+	sender := key.GetParticipant()
+	if sender == "" {
+		sender = info.GetParticipant()
+	}
+	if sender == "" && !key.GetFromMe() {
+		sender = chat
+	}
+
+	return &MessageData{
+		Id:        key.GetID(),
+		Chat:      chat,
+		Sender:    sender,
+		FromMe:    key.GetFromMe(),
+		IsGroup:   strings.HasSuffix(chat, "@g.us"),
+		PushName:  info.GetPushName(),
+		Timestamp: time.Unix(int64(info.GetMessageTimestamp()), 0).UTC(),
+		Type:      messageType(info.GetMessage()),
+		Text:      messageText(info.GetMessage()),
+		Media:     messageMedia(info.GetMessage()),
+	}
+}
+
+func offlineSyncData(_ string, data json.RawMessage) (interface{}, error) {
+	var raw struct {
+		Count int
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+
+	return &OfflineSyncData{Count: raw.Count}, nil
+}
+
+// messageType classifica a mensagem em um conjunto fixo de tipos, independente da estrutura do protobuf
+func messageType(m *waE2E.Message) string {
+	switch {
+	case m == nil:
+		return "unknown"
+	case m.Conversation != nil, m.ExtendedTextMessage != nil:
+		return "text"
+	case m.ImageMessage != nil:
+		return "image"
+	case m.VideoMessage != nil, m.PtvMessage != nil:
+		return "video"
+	case m.AudioMessage != nil:
+		return "audio"
+	case m.DocumentMessage != nil, m.DocumentWithCaptionMessage != nil:
+		return "document"
+	case m.StickerMessage != nil:
+		return "sticker"
+	case m.ContactMessage != nil, m.ContactsArrayMessage != nil:
+		return "contact"
+	case m.LocationMessage != nil, m.LiveLocationMessage != nil:
+		return "location"
+	case m.ReactionMessage != nil:
+		return "reaction"
+	case m.PollCreationMessage != nil, m.PollCreationMessageV2 != nil, m.PollCreationMessageV3 != nil:
+		return "poll"
+	case m.PollUpdateMessage != nil:
+		return "poll_vote"
+	case m.ProtocolMessage != nil:
+		return "protocol"
+	}
+	return "unknown"
+}
+
+func messageText(m *waE2E.Message) string {
+	switch {
+	case m == nil:
+		return ""
+	case m.Conversation != nil:
+		return m.GetConversation()
+	case m.ExtendedTextMessage != nil:
+		return m.GetExtendedTextMessage().GetText()
+	case m.ReactionMessage != nil:
+		return m.GetReactionMessage().GetText()
+	case m.ProtocolMessage != nil && m.GetProtocolMessage().GetEditedMessage() != nil:
+		return messageText(m.GetProtocolMessage().GetEditedMessage())
+	}
+	return ""
+}
+
+func messageMedia(m *waE2E.Message) *MediaData {
+	if m == nil {
+		return nil
+	}
+
+	if m.DocumentWithCaptionMessage != nil {
+		m = m.GetDocumentWithCaptionMessage().GetMessage()
+	}
+
+	switch {
+	case m.GetImageMessage() != nil:
+		img := m.GetImageMessage()
+		return &MediaData{Mimetype: img.GetMimetype(), Caption: img.GetCaption(), FileLength: img.GetFileLength()}
+	case m.GetVideoMessage() != nil:
+		video := m.GetVideoMessage()
+		return &MediaData{Mimetype: video.GetMimetype(), Caption: video.GetCaption(), FileLength: video.GetFileLength()}
+	case m.GetPtvMessage() != nil:
+		video := m.GetPtvMessage()
+		return &MediaData{Mimetype: video.GetMimetype(), FileLength: video.GetFileLength()}
+	case m.GetAudioMessage() != nil:
+		audio := m.GetAudioMessage()
+		return &MediaData{Mimetype: audio.GetMimetype(), FileLength: audio.GetFileLength()}
+	case m.GetDocumentMessage() != nil:
+		doc := m.GetDocumentMessage()
+		return &MediaData{Mimetype: doc.GetMimetype(), Caption: doc.GetCaption(), FileName: doc.GetFileName(), FileLength: doc.GetFileLength()}
+	case m.GetStickerMessage() != nil:
+		sticker := m.GetStickerMessage()
+		return &MediaData{Mimetype: sticker.GetMimetype(), FileLength: sticker.GetFileLength()}
+	}
+	return nil
+}
+
+func receiptData(_ string, data json.RawMessage) (interface{}, error) {
+	var raw struct {
+		MessageIDs []string
+		Chat       string
+		Sender     string
+		IsGroup    bool
+		Type       string
+		Timestamp  time.Time
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+
+	receiptType := raw.Type
+	if receiptType == "" {
+		receiptType = "delivered"
+	}
+
+	return &ReceiptData{
+		MessageIds: raw.MessageIDs,
+		Chat:       raw.Chat,
+		Sender:     raw.Sender,
+		IsGroup:    raw.IsGroup,
+		Type:       receiptType,
+		Timestamp:  raw.Timestamp,
+	}, nil
+}
+
+func presenceData(_ string, data json.RawMessage) (interface{}, error) {
+	var raw struct {
+		From        string
+		Unavailable bool
+		LastSeen    time.Time
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+
+	presence := &PresenceData{From: raw.From, Available: !raw.Unavailable}
+	if !raw.LastSeen.IsZero() {
+		presence.LastSeen = &raw.LastSeen
+	}
+
+	return presence, nil
+}
+
+func chatPresenceData(_ string, data json.RawMessage) (interface{}, error) {
+	var raw struct {
+		Chat   string
+		Sender string
+		State  string
+		Media  string
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+
+	return &ChatPresenceData{Chat: raw.Chat, Sender: raw.Sender, State: raw.State, Media: raw.Media}, nil
+}
+
+func archiveData(_ string, data json.RawMessage) (interface{}, error) {
+	var raw struct {
+		JID       string
+		Timestamp time.Time
+		Action    struct {
+			Archived bool `json:"archived"`
+		}
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+
+	return &ArchiveData{Chat: raw.JID, Archived: raw.Action.Archived, Timestamp: raw.Timestamp}, nil
+}
+
+func muteData(_ string, data json.RawMessage) (interface{}, error) {
+	var raw struct {
+		JID       string
+		Timestamp time.Time
+		Action    struct {
+			Muted            bool  `json:"muted"`
+			MuteEndTimestamp int64 `json:"muteEndTimestamp"`
+		}
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+
+	mute := &MuteData{Chat: raw.JID, Muted: raw.Action.Muted, Timestamp: raw.Timestamp}
+	// Fim negativo significa silenciado para sempre
+	if mute.Muted && raw.Action.MuteEndTimestamp > 0 {
+		mutedUntil := time.UnixMilli(raw.Action.MuteEndTimestamp).UTC()
+		mute.MutedUntil = &mutedUntil
+	}
+
+	return mute, nil
+}
+
+func pinData(_ string, data json.RawMessage) (interface{}, error) {
+	var raw struct {
+		JID       string
+		Timestamp time.Time
+		Action    struct {
+			Pinned bool `json:"pinned"`
+		}
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+
+	return &PinData{Chat: raw.JID, Pinned: raw.Action.Pinned, Timestamp: raw.Timestamp}, nil
+}
+
+func markChatAsReadData(_ string, data json.RawMessage) (interface{}, error) {
+	var raw struct {
+		JID       string
+		Timestamp time.Time
+		Action    struct {
+			Read bool `json:"read"`
+		}
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+
+	return &MarkChatAsReadData{Chat: raw.JID, Read: raw.Action.Read, Timestamp: raw.Timestamp}, nil
+}
+
+func starData(_ string, data json.RawMessage) (interface{}, error) {
+	var raw struct {
+		ChatJID   string
+		SenderJID string
+		IsFromMe  bool
+		MessageID string
+		Timestamp time.Time
+		Action    struct {
+			Starred bool `json:"starred"`
+		}
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+
+	return &StarData{
+		Chat:      raw.ChatJID,
+		Sender:    raw.SenderJID,
+		MessageId: raw.MessageID,
+		FromMe:    raw.IsFromMe,
+		Starred:   raw.Action.Starred,
+		Timestamp: raw.Timestamp,
+	}, nil
+}
+
+func deleteForMeData(_ string, data json.RawMessage) (interface{}, error) {
+	var raw struct {
+		ChatJID   string
+		SenderJID string
+		IsFromMe  bool
+		MessageID string
+		Timestamp time.Time
+		Action    struct {
+			DeleteMedia bool `json:"deleteMedia"`
+		}
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+
+	return &DeleteForMeData{
+		Chat:        raw.ChatJID,
+		Sender:      raw.SenderJID,
+		MessageId:   raw.MessageID,
+		FromMe:      raw.IsFromMe,
+		DeleteMedia: raw.Action.DeleteMedia,
+		Timestamp:   raw.Timestamp,
+	}, nil
+}
+
+func pictureData(_ string, data json.RawMessage) (interface{}, error) {
+	var raw struct {
+		JID       string
+		Author    string
+		Timestamp time.Time
+		Remove    bool
+		PictureID string
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+
+	return &PictureData{Jid: raw.JID, Author: raw.Author, Removed: raw.Remove, PictureId: raw.PictureID, Timestamp: raw.Timestamp}, nil
+}
+
+func userAboutData(_ string, data json.RawMessage) (interface{}, error) {
+	var raw struct {
+		JID       string
+		Status    string
+		Timestamp time.Time
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+
+	return &UserAboutData{Jid: raw.JID, About: raw.Status, Timestamp: raw.Timestamp}, nil
+}
+
+func identityChangeData(_ string, data json.RawMessage) (interface{}, error) {
+	var raw struct {
+		JID       string
+		Timestamp time.Time
+		Implicit  bool
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+
+	return &IdentityChangeData{Jid: raw.JID, Implicit: raw.Implicit, Timestamp: raw.Timestamp}, nil
+}
+
+func blocklistData(_ string, data json.RawMessage) (interface{}, error) {
+	var raw struct {
+		Action  string
+		Changes []struct {
+			JID    string
+			Action string
+		}
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+
+	blocklist := &BlocklistData{Action: raw.Action, Changes: []BlocklistChangeData{}}
+	for _, change := range raw.Changes {
+		blocklist.Changes = append(blocklist.Changes, BlocklistChangeData{Jid: change.JID, Action: change.Action})
+	}
+
+	return blocklist, nil
+}
+
+// connectionData lê apenas as chaves exatas montadas pelo Evolution: o evento original do whatsmeow
+// pode trazer campos com o mesmo nome em maiúsculas e outro tipo (ex.: Reason numérico)
+func connectionData(event string, data json.RawMessage) (interface{}, error) {
+	var raw map[string]interface{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+
+	connection := &ConnectionData{
+		Jid:      stringValue(raw["jid"]),
+		PushName: stringValue(raw["pushName"]),
+		Reason:   stringValue(raw["reason"]),
+	}
+
+	switch event {
+	case "Connected", "PairSuccess":
+		connection.Status = "open"
+	case "LoggedOut":
+		connection.Status = "logged_out"
+	case "Disconnected":
+		connection.Status = "close"
+	case "ConnectFailure":
+		connection.Status = "failed"
+	case "TemporaryBan":
+		connection.Status = "banned"
+		if expire, ok := raw["expire"].(float64); ok {
+			connection.ExpiresIn = int64(time.Duration(expire) / time.Second)
+		}
+	}
+
+	return connection, nil
+}
+
+func qrCodeData(_ string, data json.RawMessage) (interface{}, error) {
+	var raw struct {
+		Qrcode   string `json:"qrcode"`
+		Code     string `json:"code"`
+		Count    int    `json:"count"`
+		QrCount  int    `json:"qrcount"`
+		MaxCount int    `json:"maxCount"`
+		Reason   string `json:"reason"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+
+	count := raw.Count
+	if count == 0 {
+		count = raw.QrCount
+	}
+
+	return &QrCodeData{Qrcode: raw.Qrcode, Code: raw.Code, Count: count, MaxCount: raw.MaxCount, Reason: raw.Reason}, nil
+}
+
+func callData(_ string, data json.RawMessage) (interface{}, error) {
+	var raw struct {
+		CallID      string
+		From        string
+		CallCreator string
+		GroupJID    string
+		Reason      string
+		Timestamp   time.Time
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+
+	return &CallData{
+		CallId:    raw.CallID,
+		From:      raw.From,
+		Creator:   raw.CallCreator,
+		Group:     raw.GroupJID,
+		Reason:    raw.Reason,
+		Timestamp: raw.Timestamp,
+	}, nil
+}
+
+func labelData(_ string, data json.RawMessage) (interface{}, error) {
+	var raw struct {
+		LabelID   string
+		JID       string
+		MessageID string
+		Timestamp time.Time
+		Action    struct {
+			Name    string `json:"name"`
+			Color   int32  `json:"color"`
+			Deleted bool   `json:"deleted"`
+			Labeled bool   `json:"labeled"`
+		}
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+
+	return &LabelData{
+		LabelId:   raw.LabelID,
+		Chat:      raw.JID,
+		MessageId: raw.MessageID,
+		Name:      raw.Action.Name,
+		Color:     raw.Action.Color,
+		Deleted:   raw.Action.Deleted,
+		Labeled:   raw.Action.Labeled,
+		Timestamp: raw.Timestamp,
+	}, nil
+}
+
+func contactData(_ string, data json.RawMessage) (interface{}, error) {
+	var raw struct {
+		JID         string
+		Timestamp   time.Time
+		OldPushName string
+		NewPushName string
+		Action      struct {
+			FullName  string `json:"fullName"`
+			FirstName string `json:"firstName"`
+		}
+		Message *struct {
+			Timestamp time.Time
+		}
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+
+	contact := &ContactData{
+		Jid:         raw.JID,
+		FullName:    raw.Action.FullName,
+		FirstName:   raw.Action.FirstName,
+		PushName:    raw.NewPushName,
+		OldPushName: raw.OldPushName,
+		Timestamp:   raw.Timestamp,
+	}
+	if contact.Timestamp.IsZero() && raw.Message != nil {
+		contact.Timestamp = raw.Message.Timestamp
+	}
+
+	return contact, nil
+}
+
+func groupData(_ string, data json.RawMessage) (interface{}, error) {
+	var raw struct {
+		JID          string
+		Sender       *string
+		Timestamp    time.Time
+		Name         json.RawMessage
+		Topic        json.RawMessage
+		Participants []struct {
+			JID string
+		}
+		Join    []string
+		Leave   []string
+		Promote []string
+		Demote  []string
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+
+	group := &GroupData{
+		Jid:       raw.JID,
+		Name:      nestedString(raw.Name, "Name"),
+		Topic:     nestedString(raw.Topic, "Topic"),
+		Join:      raw.Join,
+		Leave:     raw.Leave,
+		Promote:   raw.Promote,
+		Demote:    raw.Demote,
+		Timestamp: raw.Timestamp,
+	}
+	if raw.Sender != nil {
+		group.Sender = *raw.Sender
+	}
+	for _, participant := range raw.Participants {
+		group.Participants = append(group.Participants, participant.JID)
+	}
+
+	return group, nil
+}
+
+func newsletterData(_ string, data json.RawMessage) (interface{}, error) {
+	var raw struct {
+		ID             string `json:"id"`
+		Role           string `json:"role"`
+		ThreadMetadata struct {
+			Name struct {
+				Text string `json:"text"`
+			} `json:"name"`
+		} `json:"thread_metadata"`
+		ViewerMetadata *struct {
+			Role string `json:"role"`
+		} `json:"viewer_metadata"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+
+	newsletter := &NewsletterData{Id: raw.ID, Name: raw.ThreadMetadata.Name.Text, Role: raw.Role}
+	if newsletter.Role == "" && raw.ViewerMetadata != nil {
+		newsletter.Role = raw.ViewerMetadata.Role
+	}
+
+	return newsletter, nil
+}
+
+// nestedString lê o texto de GroupName/GroupTopic, que no GroupInfo vêm como objeto
+// ({"Name": "..."}) e no JoinedGroup como campo promovido (string)
+func nestedString(data json.RawMessage, key string) string {
+	var value string
+	if err := json.Unmarshal(data, &value); err == nil {
+		return value
+	}
+
+	var object map[string]interface{}
+	if err := json.Unmarshal(data, &object); err == nil {
+		return stringValue(object[key])
+	}
+
+	return ""
+}
+
+func stringValue(value interface{}) string {
+	s, _ := value.(string)
+	return s
+}
@@ -0,0 +1,92 @@
+package event_envelope
+
+import (
+	"reflect"
+	"strings"
+	"time"
+)
+
+const schemaDraft = "https://json-schema.org/draft/2020-12/schema"
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// Schemas gera o JSON Schema do envelope v1 de cada evento a partir dos DTOs
+func Schemas() map[string]interface{} {
+	schemas := make(map[string]interface{})
+	for event, m := range mappings {
+		schemas[event] = envelopeSchema(event, schemaOf(reflect.TypeOf(m.dto)))
+	}
+	return schemas
+}
+
+func envelopeSchema(event string, data map[string]interface{}) map[string]interface{} {
+	schema := schemaOf(reflect.TypeOf(Envelope{}))
+	schema["$schema"] = schemaDraft
+	schema["title"] = event
+
+	properties := schema["properties"].(map[string]interface{})
+	properties["event"] = map[string]interface{}{"type": "string", "const": event}
+	properties["version"] = map[string]interface{}{"type": "string", "const": Version}
+	properties["data"] = data
+
+	return schema
+}
+
+// schemaOf descreve um tipo Go seguindo as tags json: campos sem omitempty são obrigatórios
+func schemaOf(t reflect.Type) map[string]interface{} {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if t == timeType {
+		return map[string]interface{}{"type": "string", "format": "date-time"}
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	case reflect.Slice, reflect.Array:
+		return map[string]interface{}{"type": "array", "items": schemaOf(t.Elem())}
+	case reflect.Map:
+		return map[string]interface{}{"type": "object", "additionalProperties": schemaOf(t.Elem())}
+	case reflect.Struct:
+		properties := make(map[string]interface{})
+		required := []string{}
+
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if !field.IsExported() {
+				continue
+			}
+
+			name, options, _ := strings.Cut(field.Tag.Get("json"), ",")
+			if name == "-" {
+				continue
+			}
+			if name == "" {
+				name = field.Name
+			}
+
+			properties[name] = schemaOf(field.Type)
+			if !strings.Contains(options, "omitempty") {
+				required = append(required, name)
+			}
+		}
+
+		return map[string]interface{}{
+			"type":       "object",
+			"properties": properties,
+			"required":   required,
+		}
+	}
+
+	// interface{} e demais tipos aceitam qualquer valor
+	return map[string]interface{}{}
+}
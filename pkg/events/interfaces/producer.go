@@ -1,6 +1,52 @@
 package producer_interfaces
 
+import (
+	"time"
+
+	webhook_model "github.com/EvolutionAPI/evolution-go/pkg/webhook/model"
+)
+
 type Producer interface {
 	Produce(queueName string, payload []byte, webhookUrl string, userID string) error
 	CreateGlobalQueues() error
 }
+
+// WebhookTarget descreve um destino HTTP, os headers extras e as credenciais usadas para assinar as entregas
+type WebhookTarget struct {
+	Url            string
+	Secret         string
+	PreviousSecret string
+	Headers        map[string]string
+	Batch          *webhook_model.WebhookBatch
+}
+
+type WebhookProducer interface {
+	Producer
+	// ProduceToTarget envia para o destino informado e para o webhook global
+	ProduceToTarget(queueName string, payload []byte, target WebhookTarget, userID string) error
+	// ProduceToTargets envia apenas para os destinos informados, sem replicar no webhook global
+	ProduceToTargets(queueName string, payload []byte, targets []WebhookTarget, userID string) error
+}
+
+type MqttProducer interface {
+	Producer
+	// PublishStatus atualiza a mensagem retida de status da instância a partir de um evento de conexão.
+	// Não depende das assinaturas, para que o tópico de status reflita sempre a conexão atual
+	PublishStatus(instanceId string, event string)
+}
+
+// BrokerHealth descreve o estado da conexão com um broker
+type BrokerHealth struct {
+	Dedicated   bool       `json:"dedicated"`
+	Connected   bool       `json:"connected"`
+	LastError   string     `json:"lastError,omitempty"`
+	LastErrorAt *time.Time `json:"lastErrorAt,omitempty"`
+}
+
+type BrokerProducer interface {
+	Producer
+	// ProduceToUrl publica um evento de instância no broker da URL informada; vazia usa o broker padrão
+	ProduceToUrl(brokerUrl string, queueName string, payload []byte, userID string) error
+	// Health retorna o estado da conexão com o broker da URL informada
+	Health(brokerUrl string) BrokerHealth
+}
@@ -0,0 +1,159 @@
+package kafka_producer
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+
+	producer_interfaces "github.com/EvolutionAPI/evolution-go/pkg/events/interfaces"
+	logger_wrapper "github.com/EvolutionAPI/evolution-go/pkg/logger"
+	"github.com/gomessguii/logger"
+	"github.com/segmentio/kafka-go"
+	"github.com/segmentio/kafka-go/compress"
+)
+
+const writeTimeout = 10 * time.Second
+
+type kafkaProducer struct {
+	writer        *kafka.Writer
+	loggerWrapper *logger_wrapper.LoggerManager
+}
+
+func NewKafkaProducer(
+	brokers []string,
+	acks string,
+	compression string,
+	loggerWrapper *logger_wrapper.LoggerManager,
+) producer_interfaces.Producer {
+	if len(brokers) == 0 {
+		return &kafkaProducer{
+			writer:        nil,
+			loggerWrapper: loggerWrapper,
+		}
+	}
+
+	requiredAcks, ok := parseRequiredAcks(acks)
+	if !ok {
+		logger.LogWarn("Invalid KAFKA_ACKS value '%s', using 'all'", acks)
+	}
+
+	codec, ok := parseCompression(compression)
+	if !ok {
+		logger.LogWarn("Invalid KAFKA_COMPRESSION value '%s', sending uncompressed", compression)
+	}
+
+	// O balanceamento por hash da chave mantém os eventos de uma mesma conversa na mesma partição
+	writer := &kafka.Writer{
+		Addr:                   kafka.TCP(brokers...),
+		Balancer:               &kafka.Hash{},
+		RequiredAcks:           requiredAcks,
+		Compression:            codec,
+		AllowAutoTopicCreation: true,
+		BatchTimeout:           10 * time.Millisecond,
+		WriteTimeout:           writeTimeout,
+	}
+
+	return &kafkaProducer{
+		writer:        writer,
+		loggerWrapper: loggerWrapper,
+	}
+}
+
+func (p *kafkaProducer) Produce(
+	queueName string,
+	payload []byte,
+	kafkaEnable string,
+	userID string,
+) error {
+	if p.writer == nil {
+		p.loggerWrapper.GetLogger(userID).LogWarn("[%s] Kafka writer is nil", userID)
+		return nil
+	}
+
+	if kafkaEnable != "global" && kafkaEnable != "enabled" && kafkaEnable != "true" {
+		return nil
+	}
+
+	message := kafka.Message{
+		Topic: queueName,
+		Key:   messageKey(payload, userID),
+		Value: payload,
+		Headers: []kafka.Header{
+			{Key: "instanceId", Value: []byte(userID)},
+		},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), writeTimeout)
+	defer cancel()
+
+	err := p.writer.WriteMessages(ctx, message)
+	if err != nil {
+		p.loggerWrapper.GetLogger(userID).LogError("[%s] Failed to publish message to Kafka topic %s: %v", userID, queueName, err)
+		return err
+	}
+
+	p.loggerWrapper.GetLogger(userID).LogInfo("[%s] Message published successfully to Kafka topic: %s", userID, queueName)
+
+	return nil
+}
+
+// CreateGlobalQueues não faz nada para Kafka producer pois os tópicos são criados automaticamente
+func (p *kafkaProducer) CreateGlobalQueues() error {
+	return nil
+}
+
+// messageKey usa o JID do chat do evento como chave, garantindo ordem por conversa.
+// Eventos sem chat (conexão, QR code, etc.) usam o id da instância
+func messageKey(payload []byte, fallback string) []byte {
+	var event struct {
+		Data struct {
+			Info struct {
+				Chat string `json:"Chat"`
+			} `json:"Info"`
+			Chat string `json:"Chat"`
+			From string `json:"From"`
+			JID  string `json:"JID"`
+		} `json:"data"`
+	}
+
+	if err := json.Unmarshal(payload, &event); err == nil {
+		for _, key := range []string{event.Data.Info.Chat, event.Data.Chat, event.Data.From, event.Data.JID} {
+			if key != "" {
+				return []byte(key)
+			}
+		}
+	}
+
+	return []byte(fallback)
+}
+
+func parseRequiredAcks(acks string) (kafka.RequiredAcks, bool) {
+	switch strings.ToLower(acks) {
+	case "", "all", "-1":
+		return kafka.RequireAll, true
+	case "one", "1":
+		return kafka.RequireOne, true
+	case "none", "0":
+		return kafka.RequireNone, true
+	default:
+		return kafka.RequireAll, false
+	}
+}
+
+func parseCompression(compression string) (compress.Compression, bool) {
+	switch strings.ToLower(compression) {
+	case "", "none":
+		return 0, true
+	case "gzip":
+		return kafka.Gzip, true
+	case "snappy":
+		return kafka.Snappy, true
+	case "lz4":
+		return kafka.Lz4, true
+	case "zstd":
+		return kafka.Zstd, true
+	default:
+		return 0, false
+	}
+}
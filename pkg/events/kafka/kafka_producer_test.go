@@ -0,0 +1,48 @@
+package kafka_producer
+
+import (
+	"testing"
+)
+
+func TestMessageKey(t *testing.T) {
+	tests := []struct {
+		name     string
+		payload  string
+		expected string
+	}{
+		{
+			name:     "Message event uses Info.Chat",
+			payload:  `{"event":"Message","data":{"Info":{"Chat":"5511999999999@s.whatsapp.net","Sender":"5511888888888@s.whatsapp.net"}}}`,
+			expected: "5511999999999@s.whatsapp.net",
+		},
+		{
+			name:     "Receipt event uses Chat",
+			payload:  `{"event":"Receipt","data":{"Chat":"120363123456789012@g.us"}}`,
+			expected: "120363123456789012@g.us",
+		},
+		{
+			name:     "Presence event uses From",
+			payload:  `{"event":"Presence","data":{"From":"5511999999999@s.whatsapp.net"}}`,
+			expected: "5511999999999@s.whatsapp.net",
+		},
+		{
+			name:     "Event without chat falls back to instance",
+			payload:  `{"event":"Connected","data":{}}`,
+			expected: "instance-id",
+		},
+		{
+			name:     "Invalid payload falls back to instance",
+			payload:  `not json`,
+			expected: "instance-id",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := string(messageKey([]byte(tt.payload), "instance-id"))
+			if result != tt.expected {
+				t.Errorf("Expected key %q, but got %q", tt.expected, result)
+			}
+		})
+	}
+}
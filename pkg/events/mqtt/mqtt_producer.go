@@ -0,0 +1,242 @@
+package mqtt_producer
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	producer_interfaces "github.com/EvolutionAPI/evolution-go/pkg/events/interfaces"
+	logger_wrapper "github.com/EvolutionAPI/evolution-go/pkg/logger"
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+	"github.com/gomessguii/logger"
+)
+
+const publishTimeout = 10 * time.Second
+
+// MqttConfig reúne as opções de conexão com o broker MQTT
+type MqttConfig struct {
+	Broker      string
+	Username    string
+	Password    string
+	ClientId    string
+	Qos         byte
+	TopicPrefix string
+	TlsCaFile   string
+	TlsInsecure bool
+}
+
+type mqttProducer struct {
+	client        mqtt.Client
+	qos           byte
+	topicPrefix   string
+	loggerWrapper *logger_wrapper.LoggerManager
+}
+
+func NewMqttProducer(
+	config MqttConfig,
+	loggerWrapper *logger_wrapper.LoggerManager,
+) producer_interfaces.MqttProducer {
+	if config.Broker == "" {
+		return &mqttProducer{
+			client:        nil,
+			loggerWrapper: loggerWrapper,
+		}
+	}
+
+	if config.Qos > 2 {
+		logger.LogWarn("Invalid MQTT_QOS value '%d', using 1", config.Qos)
+		config.Qos = 1
+	}
+
+	opts := mqtt.NewClientOptions().
+		AddBroker(config.Broker).
+		SetClientID(config.ClientId).
+		SetUsername(config.Username).
+		SetPassword(config.Password).
+		SetAutoReconnect(true).
+		SetConnectRetry(true).
+		SetConnectRetryInterval(5 * time.Second).
+		SetMaxReconnectInterval(time.Minute).
+		// A cada conexão, inclusive após reconectar, o status retido volta a online: o broker publica o
+		// last will offline sempre que a conexão cai
+		SetOnConnectHandler(func(client mqtt.Client) {
+			logger.LogInfo("Connected to MQTT broker %s", config.Broker)
+			client.Publish(serviceStatusTopic(config.TopicPrefix), config.Qos, true, "online")
+		}).
+		SetConnectionLostHandler(func(_ mqtt.Client, err error) {
+			logger.LogError("MQTT connection lost: %v", err)
+		})
+
+	// O last will avisa os assinantes quando o próprio serviço cai sem desconectar
+	opts.SetWill(serviceStatusTopic(config.TopicPrefix), "offline", config.Qos, true)
+
+	if strings.HasPrefix(config.Broker, "ssl://") || strings.HasPrefix(config.Broker, "tls://") ||
+		strings.HasPrefix(config.Broker, "mqtts://") || strings.HasPrefix(config.Broker, "wss://") {
+		tlsConfig, err := newTlsConfig(config.TlsCaFile, config.TlsInsecure)
+		if err != nil {
+			logger.LogError("Failed to load MQTT TLS configuration: %v", err)
+			return &mqttProducer{
+				client:        nil,
+				loggerWrapper: loggerWrapper,
+			}
+		}
+		opts.SetTLSConfig(tlsConfig)
+	}
+
+	// Com ConnectRetry o cliente continua tentando em segundo plano se o broker estiver indisponível
+	client := mqtt.NewClient(opts)
+	token := client.Connect()
+	if !token.WaitTimeout(publishTimeout) {
+		logger.LogWarn("MQTT broker %s not reachable yet, retrying in background", config.Broker)
+	} else if err := token.Error(); err != nil {
+		logger.LogError("Failed to connect to MQTT broker: %v", err)
+	}
+
+	p := &mqttProducer{
+		client:        client,
+		qos:           config.Qos,
+		topicPrefix:   config.TopicPrefix,
+		loggerWrapper: loggerWrapper,
+	}
+
+	return p
+}
+
+func (p *mqttProducer) Produce(
+	queueName string,
+	payload []byte,
+	mqttEnable string,
+	userID string,
+) error {
+	if p.client == nil {
+		p.loggerWrapper.GetLogger(userID).LogWarn("[%s] MQTT client is nil", userID)
+		return nil
+	}
+
+	var topic, event string
+	switch mqttEnable {
+	case "global":
+		event = queueName
+		topic = globalTopic(p.topicPrefix, event)
+	case "enabled", "true":
+		event = queueEvent(queueName)
+		topic = instanceTopic(p.topicPrefix, userID, event)
+	default:
+		return nil
+	}
+
+	if err := p.publish(topic, payload, false); err != nil {
+		p.loggerWrapper.GetLogger(userID).LogError("[%s] Failed to publish event to MQTT topic %s: %v", userID, topic, err)
+		return err
+	}
+
+	p.loggerWrapper.GetLogger(userID).LogInfo("[%s] Event published to MQTT topic %s", userID, topic)
+
+	return nil
+}
+
+func (p *mqttProducer) PublishStatus(instanceId string, event string) {
+	if p.client == nil {
+		return
+	}
+
+	event = strings.ToLower(event)
+	status, ok := connectionStatus(event)
+	if !ok {
+		return
+	}
+
+	statusPayload, err := json.Marshal(map[string]interface{}{
+		"instanceId": instanceId,
+		"status":     status,
+		"event":      event,
+		"timestamp":  time.Now().Unix(),
+	})
+	if err != nil {
+		return
+	}
+
+	topic := instanceStatusTopic(p.topicPrefix, instanceId)
+	if err := p.publish(topic, statusPayload, true); err != nil {
+		p.loggerWrapper.GetLogger(instanceId).LogError("[%s] Failed to publish MQTT status to %s: %v", instanceId, topic, err)
+	}
+}
+
+func (p *mqttProducer) publish(topic string, payload []byte, retained bool) error {
+	token := p.client.Publish(topic, p.qos, retained, payload)
+	if !token.WaitTimeout(publishTimeout) {
+		return fmt.Errorf("timeout publishing to %s", topic)
+	}
+
+	return token.Error()
+}
+
+// CreateGlobalQueues não faz nada para MQTT producer pois os tópicos não precisam ser criados
+func (p *mqttProducer) CreateGlobalQueues() error {
+	return nil
+}
+
+// connectionStatus traduz os eventos de conexão para o status publicado no tópico retido
+func connectionStatus(event string) (string, bool) {
+	switch event {
+	case "connected", "pairsuccess":
+		return "connected", true
+	case "disconnected", "loggedout", "connectfailure", "temporaryban":
+		return "disconnected", true
+	}
+
+	return "", false
+}
+
+func serviceStatusTopic(prefix string) string {
+	return fmt.Sprintf("%s/status", prefix)
+}
+
+func instanceStatusTopic(prefix string, instanceId string) string {
+	return fmt.Sprintf("%s/%s/status", prefix, instanceId)
+}
+
+func instanceTopic(prefix string, instanceId string, event string) string {
+	return fmt.Sprintf("%s/%s/%s", prefix, instanceId, event)
+}
+
+func globalTopic(prefix string, event string) string {
+	return fmt.Sprintf("%s/global/%s", prefix, event)
+}
+
+// queueEvent extrai o nome do evento da fila no formato instanceId.evento
+func queueEvent(queueName string) string {
+	if _, event, found := strings.Cut(queueName, "."); found {
+		return event
+	}
+
+	return queueName
+}
+
+func newTlsConfig(caFile string, insecure bool) (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: insecure,
+	}
+
+	if caFile == "" {
+		return tlsConfig, nil
+	}
+
+	caCert, err := os.ReadFile(caFile)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler CA: %v", err)
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(caCert) {
+		return nil, fmt.Errorf("nenhum certificado válido em %s", caFile)
+	}
+	tlsConfig.RootCAs = pool
+
+	return tlsConfig, nil
+}
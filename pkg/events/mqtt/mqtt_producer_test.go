@@ -0,0 +1,89 @@
+package mqtt_producer
+
+import (
+	"testing"
+)
+
+func TestConnectionStatus(t *testing.T) {
+	tests := []struct {
+		name     string
+		event    string
+		expected string
+		ok       bool
+	}{
+		{
+			name:     "Connected",
+			event:    "connected",
+			expected: "connected",
+			ok:       true,
+		},
+		{
+			name:     "Pair success counts as connected",
+			event:    "pairsuccess",
+			expected: "connected",
+			ok:       true,
+		},
+		{
+			name:     "Logged out",
+			event:    "loggedout",
+			expected: "disconnected",
+			ok:       true,
+		},
+		{
+			name:     "Temporary ban",
+			event:    "temporaryban",
+			expected: "disconnected",
+			ok:       true,
+		},
+		{
+			name:  "Other events do not change the status",
+			event: "message",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, ok := connectionStatus(tt.event)
+			if result != tt.expected || ok != tt.ok {
+				t.Errorf("Expected (%q, %v), but got (%q, %v)", tt.expected, tt.ok, result, ok)
+			}
+		})
+	}
+}
+
+func TestTopics(t *testing.T) {
+	tests := []struct {
+		name     string
+		topic    string
+		expected string
+	}{
+		{
+			name:     "Instance event",
+			topic:    instanceTopic("evolution", "instance-id", queueEvent("instance-id.message")),
+			expected: "evolution/instance-id/message",
+		},
+		{
+			name:     "Global event",
+			topic:    globalTopic("evolution", "message"),
+			expected: "evolution/global/message",
+		},
+		{
+			name:     "Instance status",
+			topic:    instanceStatusTopic("evolution", "instance-id"),
+			expected: "evolution/instance-id/status",
+		},
+		{
+			name:     "Service status",
+			topic:    serviceStatusTopic("evolution"),
+			expected: "evolution/status",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.topic != tt.expected {
+				t.Errorf("Expected topic %q, but got %q", tt.expected, tt.topic)
+			}
+		})
+	}
+}
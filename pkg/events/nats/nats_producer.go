@@ -1,14 +1,55 @@
 package nats_producer
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
 	producer_interfaces "github.com/EvolutionAPI/evolution-go/pkg/events/interfaces"
 	logger_wrapper "github.com/EvolutionAPI/evolution-go/pkg/logger"
 	"github.com/gomessguii/logger"
+	"github.com/google/uuid"
 	"github.com/nats-io/nats.go"
 )
 
+const (
+	publishAckWait    = 5 * time.Second
+	publishRetryBase  = 200 * time.Millisecond
+	brokerIdleTimeout = 10 * time.Minute // conexões de instâncias sem uso são fechadas após esse tempo
+)
+
+// JetStreamConfig habilita a publicação com confirmação em um stream JetStream
+type JetStreamConfig struct {
+	Enabled  bool
+	Stream   string
+	Subjects []string
+	Replicas int
+	MaxAge   time.Duration
+	Retries  int
+	// ProvisionDedicated permite criar e alterar o stream também nos servidores próprios das instâncias.
+	// Desligado, esses servidores precisam já ter um stream que capture os subjects publicados
+	ProvisionDedicated bool
+}
+
+// natsConnection é a conexão com um servidor NATS e o contexto JetStream, quando habilitado
+type natsConnection struct {
+	conn        *nats.Conn
+	js          nats.JetStreamContext
+	lastUsed    atomic.Int64
+	mu          sync.Mutex // protege o último erro
+	lastError   string
+	lastErrorAt *time.Time
+}
+
 type natsProducer struct {
-	conn              *nats.Conn
+	shared            *natsConnection // servidor de NATS_URL
+	poolMu            sync.Mutex
+	pool              map[string]*natsConnection // servidores próprios das instâncias, por URL
+	janitor           sync.Once
+	jetStream         JetStreamConfig
 	natsGlobalEnabled bool
 	natsGlobalEvents  []string
 	loggerWrapper     *logger_wrapper.LoggerManager
@@ -18,24 +59,161 @@ func NewNatsProducer(
 	url string,
 	natsGlobalEnabled bool,
 	natsGlobalEvents []string,
+	jetStream JetStreamConfig,
+	subjects SubjectConfig,
 	loggerWrapper *logger_wrapper.LoggerManager,
-) producer_interfaces.Producer {
-	conn, err := nats.Connect(url)
+) producer_interfaces.BrokerProducer {
+	p := &natsProducer{
+		shared:            &natsConnection{},
+		pool:              make(map[string]*natsConnection),
+		natsGlobalEnabled: false,
+		natsGlobalEvents:  nil,
+		loggerWrapper:     loggerWrapper,
+	}
+
+	if url == "" {
+		return p
+	}
+
+	// Sem NATS_JETSTREAM_SUBJECTS o stream captura os subjects gerados pelo template e pelo prefixo global
+	if len(jetStream.Subjects) == 0 {
+		jetStream.Subjects = subjects.StreamSubjects(natsGlobalEnabled)
+	}
+	p.jetStream = jetStream
+
+	shared, err := p.connect(url, false)
 	if err != nil {
 		logger.LogError("Failed to connect to NATS: %v", err)
-		return &natsProducer{
-			conn:              nil,
-			natsGlobalEnabled: false,
-			natsGlobalEvents:  nil,
-			loggerWrapper:     loggerWrapper,
+		return p
+	}
+
+	p.shared = shared
+	p.natsGlobalEnabled = natsGlobalEnabled
+	p.natsGlobalEvents = natsGlobalEvents
+
+	return p
+}
+
+// connect abre uma conexão com o servidor e, com JetStream habilitado, cria o contexto de publicação.
+// Em servidores próprios (dedicated) o stream só é provisionado com NATS_JETSTREAM_PROVISION_DEDICATED
+func (p *natsProducer) connect(url string, dedicated bool) (*natsConnection, error) {
+	c := &natsConnection{}
+	provision := !dedicated || p.jetStream.ProvisionDedicated
+
+	// RetryOnFailedConnect mantém o produtor tentando conectar em segundo plano
+	// em vez de desistir se o servidor estiver indisponível na inicialização
+	conn, err := nats.Connect(url,
+		nats.RetryOnFailedConnect(true),
+		nats.MaxReconnects(-1),
+		nats.ReconnectWait(2*time.Second),
+		nats.ConnectHandler(func(nc *nats.Conn) {
+			logger.LogInfo("Connected to NATS")
+			if provision {
+				p.provisionStream(nc)
+			}
+		}),
+		nats.ReconnectHandler(func(nc *nats.Conn) {
+			logger.LogInfo("Reconnected to NATS")
+			if provision {
+				p.provisionStream(nc)
+			}
+		}),
+		nats.DisconnectErrHandler(func(_ *nats.Conn, err error) {
+			if err != nil {
+				logger.LogWarn("Disconnected from NATS: %v", err)
+				c.setError(err)
+			}
+		}),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	c.conn = conn
+
+	if p.jetStream.Enabled {
+		js, err := conn.JetStream()
+		if err != nil {
+			logger.LogError("Failed to create NATS JetStream context: %v", err)
+			return c, nil
 		}
+		c.js = js
 	}
 
-	return &natsProducer{
-		conn:              conn,
-		natsGlobalEnabled: natsGlobalEnabled,
-		natsGlobalEvents:  natsGlobalEvents,
-		loggerWrapper:     loggerWrapper,
+	return c, nil
+}
+
+// connection retorna a conexão do servidor próprio de uma instância, criando-a na primeira publicação.
+// A conexão é aberta fora do lock para que um servidor lento não bloqueie as demais instâncias
+func (p *natsProducer) connection(brokerUrl string) (*natsConnection, error) {
+	p.poolMu.Lock()
+	c, ok := p.pool[brokerUrl]
+	p.poolMu.Unlock()
+
+	if !ok {
+		dialed, err := p.connect(brokerUrl, true)
+		if err != nil {
+			return nil, err
+		}
+
+		// Outra publicação pode ter aberto a mesma conexão enquanto esta era estabelecida
+		p.poolMu.Lock()
+		c, ok = p.pool[brokerUrl]
+		if !ok {
+			c = dialed
+			p.pool[brokerUrl] = c
+		}
+		p.poolMu.Unlock()
+
+		if ok {
+			dialed.conn.Close()
+		}
+		p.janitor.Do(func() { go p.closeIdleConnections() })
+	}
+	c.lastUsed.Store(time.Now().UnixNano())
+
+	return c, nil
+}
+
+// closeIdleConnections fecha as conexões de instâncias que pararam de publicar,
+// como as de URLs que foram trocadas ou de instâncias removidas
+func (p *natsProducer) closeIdleConnections() {
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		limit := time.Now().Add(-brokerIdleTimeout).UnixNano()
+
+		p.poolMu.Lock()
+		for brokerUrl, c := range p.pool {
+			if c.lastUsed.Load() < limit {
+				delete(p.pool, brokerUrl)
+				c.conn.Close()
+				logger.LogInfo("Closed idle NATS connection")
+			}
+		}
+		p.poolMu.Unlock()
+	}
+}
+
+func (c *natsConnection) setError(err error) {
+	now := time.Now()
+
+	c.mu.Lock()
+	c.lastError = err.Error()
+	c.lastErrorAt = &now
+	c.mu.Unlock()
+}
+
+func (c *natsConnection) health(dedicated bool) producer_interfaces.BrokerHealth {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return producer_interfaces.BrokerHealth{
+		Dedicated:   dedicated,
+		Connected:   c.conn != nil && c.conn.IsConnected(),
+		LastError:   c.lastError,
+		LastErrorAt: c.lastErrorAt,
 	}
 }
 
@@ -44,19 +222,60 @@ func (p *natsProducer) Produce(
 	payload []byte,
 	natsEnable string,
 	userID string,
+) error {
+	return p.produce(p.shared, queueName, payload, natsEnable, userID)
+}
+
+// ProduceToUrl publica o evento da instância no servidor dela; sem URL própria usa o de NATS_URL
+func (p *natsProducer) ProduceToUrl(brokerUrl string, queueName string, payload []byte, userID string) error {
+	if brokerUrl == "" {
+		return p.Produce(queueName, payload, "enabled", userID)
+	}
+
+	c, err := p.connection(brokerUrl)
+	if err != nil {
+		return fmt.Errorf("failed to connect to instance NATS server: %v", err)
+	}
+
+	return p.produce(c, queueName, payload, "enabled", userID)
+}
+
+// Health retorna o estado da conexão usada pela instância; a conexão própria só existe após a primeira publicação
+func (p *natsProducer) Health(brokerUrl string) producer_interfaces.BrokerHealth {
+	if brokerUrl == "" {
+		return p.shared.health(false)
+	}
+
+	p.poolMu.Lock()
+	c, ok := p.pool[brokerUrl]
+	p.poolMu.Unlock()
+
+	if !ok {
+		return producer_interfaces.BrokerHealth{Dedicated: true}
+	}
+	return c.health(true)
+}
+
+func (p *natsProducer) produce(
+	c *natsConnection,
+	queueName string,
+	payload []byte,
+	natsEnable string,
+	userID string,
 ) error {
 	p.loggerWrapper.GetLogger(userID).LogInfo("[%s] NATS Producer - Starting produce for subject: %s", userID, queueName)
 	p.loggerWrapper.GetLogger(userID).LogInfo("[%s] NATS Producer - Global enabled: %v", userID, p.natsGlobalEnabled)
 
-	if p.conn == nil {
+	if c.conn == nil {
 		p.loggerWrapper.GetLogger(userID).LogWarn("[%s] NATS connection is nil", userID)
 		return nil
 	}
 
 	if natsEnable == "global" {
 		p.loggerWrapper.GetLogger(userID).LogInfo("[%s] Publishing to global subject: %s", userID, queueName)
-		err := p.conn.Publish(queueName, payload)
+		err := p.publish(c, queueName, payload)
 		if err != nil {
+			c.setError(err)
 			p.loggerWrapper.GetLogger(userID).LogError("[%s] Failed to publish message to subject %s: %v", userID, queueName, err)
 			return err
 		}
@@ -64,8 +283,9 @@ func (p *natsProducer) Produce(
 	}
 
 	if natsEnable == "enabled" {
-		err := p.conn.Publish(queueName, payload)
+		err := p.publish(c, queueName, payload)
 		if err != nil {
+			c.setError(err)
 			p.loggerWrapper.GetLogger(userID).LogError("[%s] Failed to publish message to instance subject %s: %v", userID, queueName, err)
 			return err
 		}
@@ -75,7 +295,142 @@ func (p *natsProducer) Produce(
 	return nil
 }
 
-// CreateGlobalQueues não faz nada para NATS producer pois os subjects são criados dinamicamente
+// publish usa o core NATS (fire-and-forget) ou, com JetStream habilitado, aguarda o ack do stream.
+// O mesmo Nats-Msg-Id é reenviado em todas as tentativas para que o servidor descarte duplicatas
+func (p *natsProducer) publish(c *natsConnection, subject string, payload []byte) error {
+	if c.js == nil {
+		return c.conn.Publish(subject, payload)
+	}
+
+	msgId := messageId(subject, payload)
+
+	var err error
+	for attempt := 0; attempt <= p.jetStream.Retries; attempt++ {
+		if attempt > 0 {
+			time.Sleep(publishRetryBase * time.Duration(1<<(attempt-1)))
+		}
+
+		_, err = c.js.Publish(subject, payload, nats.MsgId(msgId), nats.AckWait(publishAckWait))
+		if err == nil {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("jetstream publish failed after %d attempts: %w", p.jetStream.Retries+1, err)
+}
+
+// CreateGlobalQueues cria ou valida o stream JetStream; no modo core os subjects são criados dinamicamente
 func (p *natsProducer) CreateGlobalQueues() error {
+	if p.shared.js == nil {
+		return nil
+	}
+
+	if !p.shared.conn.IsConnected() {
+		// O stream será provisionado pelo ConnectHandler assim que a conexão for estabelecida
+		return nil
+	}
+
+	return p.ensureStream(p.shared.js)
+}
+
+// provisionStream é chamado a cada (re)conexão, pois o stream pode ter sido removido
+// ou o servidor pode ter sido trocado enquanto o produtor estava desconectado
+func (p *natsProducer) provisionStream(nc *nats.Conn) {
+	if !p.jetStream.Enabled {
+		return
+	}
+
+	js, err := nc.JetStream()
+	if err != nil {
+		logger.LogError("Failed to create NATS JetStream context: %v", err)
+		return
+	}
+
+	if err := p.ensureStream(js); err != nil {
+		logger.LogError("Failed to provision NATS JetStream stream %s: %v", p.jetStream.Stream, err)
+	}
+}
+
+// ensureStream cria o stream se ele não existir ou acrescenta os subjects que estiverem faltando
+func (p *natsProducer) ensureStream(js nats.JetStreamContext) error {
+	info, err := js.StreamInfo(p.jetStream.Stream)
+	if errors.Is(err, nats.ErrStreamNotFound) {
+		_, err = js.AddStream(&nats.StreamConfig{
+			Name:       p.jetStream.Stream,
+			Subjects:   p.jetStream.Subjects,
+			Storage:    nats.FileStorage,
+			Retention:  nats.LimitsPolicy,
+			Replicas:   p.jetStream.Replicas,
+			MaxAge:     p.jetStream.MaxAge,
+			Duplicates: 2 * time.Minute,
+		})
+		if err != nil {
+			return fmt.Errorf("erro ao criar stream: %v", err)
+		}
+
+		logger.LogInfo("NATS JetStream stream %s created with subjects %v", p.jetStream.Stream, p.jetStream.Subjects)
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("erro ao consultar stream: %v", err)
+	}
+
+	missing := missingSubjects(info.Config.Subjects, p.jetStream.Subjects)
+	if len(missing) == 0 {
+		logger.LogInfo("NATS JetStream stream %s validated", p.jetStream.Stream)
+		return nil
+	}
+
+	streamConfig := info.Config
+	streamConfig.Subjects = append(streamConfig.Subjects, missing...)
+	if _, err := js.UpdateStream(&streamConfig); err != nil {
+		return fmt.Errorf("erro ao atualizar subjects do stream: %v", err)
+	}
+
+	logger.LogInfo("NATS JetStream stream %s updated with subjects %v", p.jetStream.Stream, missing)
+
 	return nil
 }
+
+func missingSubjects(current []string, expected []string) []string {
+	existing := make(map[string]bool, len(current))
+	for _, subject := range current {
+		existing[subject] = true
+	}
+
+	var missing []string
+	for _, subject := range expected {
+		if !existing[subject] {
+			missing = append(missing, subject)
+		}
+	}
+
+	return missing
+}
+
+// messageId usa o id da mensagem do WhatsApp quando o evento tem um, para que o mesmo
+// evento publicado de novo seja descartado pelo stream; os demais recebem um id único
+func messageId(subject string, payload []byte) string {
+	var event struct {
+		Event   string `json:"event"`
+		Version string `json:"version"`
+		Data    struct {
+			Id   string `json:"id"`
+			Info struct {
+				ID string `json:"ID"`
+			} `json:"Info"`
+		} `json:"data"`
+	}
+
+	if err := json.Unmarshal(payload, &event); err == nil {
+		if event.Data.Info.ID != "" {
+			return subject + ":" + event.Data.Info.ID
+		}
+		// No envelope v1 o id da mensagem fica em data.id
+		if event.Version != "" && event.Event == "Message" && event.Data.Id != "" {
+			return subject + ":" + event.Data.Id
+		}
+	}
+
+	return uuid.New().String()
+}
@@ -0,0 +1,80 @@
+package nats_producer
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestMessageId(t *testing.T) {
+	payload := []byte(`{"event":"Message","data":{"Info":{"ID":"3EB0C767D26A3D1A4E1B"}}}`)
+
+	if got := messageId("a1b2c3.message", payload); got != "a1b2c3.message:3EB0C767D26A3D1A4E1B" {
+		t.Errorf("messageId() = %q, want subject and WhatsApp id", got)
+	}
+
+	if messageId("message", payload) == messageId("a1b2c3.message", payload) {
+		t.Error("messageId() should differ between subjects")
+	}
+
+	envelope := []byte(`{"event":"Message","version":"v1","data":{"id":"3EB0C767D26A3D1A4E1B"}}`)
+	if got := messageId("a1b2c3.message", envelope); got != "a1b2c3.message:3EB0C767D26A3D1A4E1B" {
+		t.Errorf("messageId() = %q, want subject and WhatsApp id from v1 envelope", got)
+	}
+
+	first := messageId("a1b2c3.connected", []byte(`{"event":"Connected","data":{}}`))
+	second := messageId("a1b2c3.connected", []byte(`{"event":"Connected","data":{}}`))
+	if first == second || strings.Contains(first, ":") {
+		t.Errorf("messageId() without WhatsApp id should be unique, got %q and %q", first, second)
+	}
+}
+
+func TestMissingSubjects(t *testing.T) {
+	tests := []struct {
+		name     string
+		current  []string
+		expected []string
+		want     []string
+	}{
+		{"all present", []string{"*.*", "*"}, []string{"*.*"}, nil},
+		{"one missing", []string{"*.*"}, []string{"*.*", "*"}, []string{"*"}},
+		{"empty stream", nil, []string{"*.*"}, []string{"*.*"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := missingSubjects(tt.current, tt.expected); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("missingSubjects() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSubjectConfig(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   SubjectConfig
+		global   string
+		instance string
+		stream   []string
+	}{
+		{"default", SubjectConfig{}, "message", "a1b2c3.message", []string{"*.*", "*"}},
+		{"prefix", SubjectConfig{GlobalPrefix: "evolution.global"}, "evolution.global.message", "a1b2c3.message", []string{"*.*", "evolution.global.*"}},
+		{"template", SubjectConfig{InstanceTemplate: "evolution.{instanceName}.{event}"}, "message", "evolution.vendas_sp.message", []string{"evolution.*.*", "*"}},
+		{"overlap", SubjectConfig{GlobalPrefix: "evolution.global", InstanceTemplate: "evolution.{instanceName}.{event}"}, "evolution.global.message", "evolution.vendas_sp.message", []string{"evolution.*.*"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.config.Global("Message"); got != tt.global {
+				t.Errorf("Global() = %q, want %q", got, tt.global)
+			}
+			if got := tt.config.Instance("a1b2c3", "vendas.sp", "Message"); got != tt.instance {
+				t.Errorf("Instance() = %q, want %q", got, tt.instance)
+			}
+			if got := tt.config.StreamSubjects(true); !reflect.DeepEqual(got, tt.stream) {
+				t.Errorf("StreamSubjects() = %v, want %v", got, tt.stream)
+			}
+		})
+	}
+}
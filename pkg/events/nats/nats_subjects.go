@@ -0,0 +1,68 @@
+package nats_producer
+
+import "strings"
+
+// DefaultSubjectTemplate mantém o formato original dos subjects por instância: instanceId.evento
+const DefaultSubjectTemplate = "{instanceId}.{event}"
+
+// SubjectConfig define os nomes dos subjects publicados. GlobalPrefix é prefixado aos subjects
+// globais (evolution.global -> evolution.global.message) e InstanceTemplate monta os subjects das
+// instâncias a partir de {instanceId}, {instanceName} e {event}
+type SubjectConfig struct {
+	GlobalPrefix     string
+	InstanceTemplate string
+}
+
+// subjectToken substitui os caracteres que não podem aparecer em um token de subject NATS
+var subjectToken = strings.NewReplacer(".", "_", " ", "_", "*", "_", ">", "_")
+
+// Global retorna o subject global do evento
+func (c SubjectConfig) Global(event string) string {
+	event = strings.ToLower(event)
+	if c.GlobalPrefix == "" {
+		return event
+	}
+	return c.GlobalPrefix + "." + event
+}
+
+// Instance aplica o template ao evento da instância; o nome da instância é normalizado para um único token
+func (c SubjectConfig) Instance(instanceId string, instanceName string, event string) string {
+	return c.render(subjectToken.Replace(instanceId), subjectToken.Replace(instanceName), strings.ToLower(event))
+}
+
+func (c SubjectConfig) render(instanceId string, instanceName string, event string) string {
+	template := c.InstanceTemplate
+	if template == "" {
+		template = DefaultSubjectTemplate
+	}
+
+	return strings.NewReplacer("{instanceId}", instanceId, "{instanceName}", instanceName, "{event}", event).Replace(template)
+}
+
+// StreamSubjects deriva os subjects capturados pelo stream JetStream quando NATS_JETSTREAM_SUBJECTS
+// não é informado: o template da instância com wildcards e, com publicação global, o prefixo global
+func (c SubjectConfig) StreamSubjects(global bool) []string {
+	subjects := []string{c.render("*", "*", "*")}
+
+	// O stream não aceita subjects sobrepostos; o global fica de fora se o do template já o cobre
+	if global && !covers(subjects[0], c.Global("*")) {
+		subjects = append(subjects, c.Global("*"))
+	}
+	return subjects
+}
+
+// covers indica se todo subject que casa com subject também casa com pattern (wildcards * apenas)
+func covers(pattern string, subject string) bool {
+	patternTokens := strings.Split(pattern, ".")
+	subjectTokens := strings.Split(subject, ".")
+	if len(patternTokens) != len(subjectTokens) {
+		return false
+	}
+
+	for i, token := range patternTokens {
+		if token != "*" && token != subjectTokens[i] {
+			return false
+		}
+	}
+	return true
+}
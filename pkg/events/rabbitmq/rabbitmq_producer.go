@@ -1,41 +1,87 @@
 package rabbitmq_producer
 
 import (
+	"context"
 	"fmt"
 	"net/url"
-	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	producer_interfaces "github.com/EvolutionAPI/evolution-go/pkg/events/interfaces"
+	"github.com/EvolutionAPI/evolution-go/pkg/internal/event_types"
 	logger_wrapper "github.com/EvolutionAPI/evolution-go/pkg/logger"
 	"github.com/gomessguii/logger"
 	amqp "github.com/rabbitmq/amqp091-go"
 )
 
+const (
+	channelPoolSize   = 16
+	confirmTimeout    = 5 * time.Second
+	brokerIdleTimeout = 10 * time.Minute // conexões de instâncias sem uso são fechadas após esse tempo
+)
+
+type pooledChannel struct {
+	channel    *amqp.Channel
+	generation uint64
+}
+
+// rabbitConnection é a conexão com um broker e o pool de canais abertos nela
+type rabbitConnection struct {
+	mu          sync.Mutex // protege conn, generation e closed; fica bloqueado durante a reconexão
+	conn        *amqp.Connection
+	current     atomic.Pointer[amqp.Connection] // cópia de conn lida pelo health sem esperar a reconexão
+	generation  uint64                          // incrementada a cada reconexão para invalidar canais do pool
+	closed      bool                            // conexão removida do pool por inatividade
+	channels    chan *pooledChannel
+	declared    sync.Map // filas e exchanges já declarados na conexão atual
+	connStr     string
+	lastUsed    atomic.Int64
+	errMu       sync.Mutex // protege o último erro
+	lastError   string
+	lastErrorAt *time.Time
+}
+
 type rabbitMQProducer struct {
-	conn               *amqp.Connection
+	shared             *rabbitConnection // broker de AMQP_URL
+	poolMu             sync.Mutex
+	pool               map[string]*rabbitConnection // brokers próprios das instâncias, por URL
+	janitor            sync.Once
 	amqpGlobalEnabled  bool
 	amqpGlobalEvents   []string
 	amqpSpecificEvents []string
-	connStr            string
+	exchange           string
 	maxRetries         int
 	loggerWrapper      *logger_wrapper.LoggerManager
 }
 
+func newRabbitConnection(conn *amqp.Connection, connStr string) *rabbitConnection {
+	c := &rabbitConnection{
+		conn:     conn,
+		channels: make(chan *pooledChannel, channelPoolSize),
+		connStr:  connStr,
+	}
+	c.current.Store(conn)
+
+	return c
+}
+
 func NewRabbitMQProducer(
 	conn *amqp.Connection,
 	amqpGlobalEnabled bool,
 	amqpGlobalEvents []string,
 	amqpSpecificEvents []string,
+	exchange string,
 	connStr string,
 	loggerWrapper *logger_wrapper.LoggerManager,
-) producer_interfaces.Producer {
+) producer_interfaces.BrokerProducer {
 	producer := &rabbitMQProducer{
-		conn:               conn,
+		shared:             newRabbitConnection(conn, connStr),
+		pool:               make(map[string]*rabbitConnection),
 		amqpGlobalEnabled:  amqpGlobalEnabled,
 		amqpGlobalEvents:   amqpGlobalEvents,
 		amqpSpecificEvents: amqpSpecificEvents,
-		connStr:            connStr,
+		exchange:           exchange,
 		maxRetries:         3,
 		loggerWrapper:      loggerWrapper,
 	}
@@ -44,7 +90,7 @@ func NewRabbitMQProducer(
 }
 
 // maskConnectionString masks sensitive information in the connection string for logging
-func (p *rabbitMQProducer) maskConnectionString(connStr string) string {
+func maskConnectionString(connStr string) string {
 	if connStr == "" {
 		return "empty"
 	}
@@ -65,29 +111,31 @@ func (p *rabbitMQProducer) maskConnectionString(connStr string) string {
 }
 
 // handleConnectionClose monitors connection close events and logs them
-func (p *rabbitMQProducer) handleConnectionClose() {
-	if p.conn == nil {
+func (c *rabbitConnection) handleConnectionClose(conn *amqp.Connection) {
+	if conn == nil {
 		return
 	}
 
-	closeChan := make(chan *amqp.Error)
-	p.conn.NotifyClose(closeChan)
+	closeChan := make(chan *amqp.Error, 1)
+	conn.NotifyClose(closeChan)
 
 	closeErr := <-closeChan
 	if closeErr != nil {
-		logger.LogWarn("RabbitMQ connection closed unexpectedly: %v", closeErr)
+		logger.LogWarn("RabbitMQ connection %s closed unexpectedly: %v", maskConnectionString(c.connStr), closeErr)
+		c.setError(closeErr)
 		logger.LogInfo("Connection will be re-established on next message send")
 	} else {
 		logger.LogInfo("RabbitMQ connection closed gracefully")
 	}
 }
 
-func (p *rabbitMQProducer) reconnect() error {
-	if p.connStr == "" {
+// reconnect deve ser chamado com c.mu bloqueado
+func (c *rabbitConnection) reconnect() error {
+	if c.connStr == "" {
 		return fmt.Errorf("connection string is empty - RabbitMQ URL not configured")
 	}
 
-	logger.LogInfo("Starting RabbitMQ reconnection process with URL: %s", p.maskConnectionString(p.connStr))
+	logger.LogInfo("Starting RabbitMQ reconnection process with URL: %s", maskConnectionString(c.connStr))
 
 	var err error
 	for i := 0; i < 3; i++ {
@@ -99,12 +147,19 @@ func (p *rabbitMQProducer) reconnect() error {
 			Locale:    "en_US",
 		}
 
-		p.conn, err = amqp.DialConfig(p.connStr, config)
+		var conn *amqp.Connection
+		conn, err = amqp.DialConfig(c.connStr, config)
 		if err == nil {
 			logger.LogInfo("Reconectado com sucesso ao RabbitMQ com heartbeat de 30s")
 
+			// Canais e declarações da conexão anterior deixam de valer
+			c.conn = conn
+			c.current.Store(conn)
+			c.generation++
+			c.declared.Clear()
+
 			// Set up connection close notification
-			go p.handleConnectionClose()
+			go c.handleConnectionClose(conn)
 			return nil
 		}
 
@@ -116,31 +171,187 @@ func (p *rabbitMQProducer) reconnect() error {
 	return fmt.Errorf("falha ao reconectar após 3 tentativas: %v", err)
 }
 
-func (p *rabbitMQProducer) ensureConnection() error {
-	if p.conn == nil || p.conn.IsClosed() {
-		return p.reconnect()
+// ensureConnection é seguro para uso concorrente: apenas uma goroutine reconecta
+// e as demais aguardam e reutilizam a nova conexão
+func (c *rabbitConnection) ensureConnection() (*amqp.Connection, uint64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		return nil, 0, fmt.Errorf("connection was closed after being idle")
 	}
-	return nil
+
+	if c.conn == nil || c.conn.IsClosed() {
+		if err := c.reconnect(); err != nil {
+			return nil, 0, err
+		}
+	}
+
+	return c.conn, c.generation, nil
+}
+
+// acquireChannel retira um canal do pool ou abre um novo, já em modo confirm
+func (c *rabbitConnection) acquireChannel() (*pooledChannel, error) {
+	conn, generation, err := c.ensureConnection()
+	if err != nil {
+		return nil, fmt.Errorf("falha ao garantir conexão: %v", err)
+	}
+
+	for {
+		select {
+		case pooled := <-c.channels:
+			if pooled.generation == generation && !pooled.channel.IsClosed() {
+				return pooled, nil
+			}
+			pooled.channel.Close()
+		default:
+			channel, err := conn.Channel()
+			if err != nil {
+				return nil, fmt.Errorf("falha ao abrir canal: %v", err)
+			}
+
+			if err := channel.Confirm(false); err != nil {
+				channel.Close()
+				return nil, fmt.Errorf("falha ao configurar confirms do canal: %v", err)
+			}
+
+			return &pooledChannel{channel: channel, generation: generation}, nil
+		}
+	}
+}
+
+// releaseChannel devolve o canal ao pool; canais fechados, de uma conexão antiga
+// ou excedentes são descartados
+func (c *rabbitConnection) releaseChannel(pooled *pooledChannel) {
+	if !c.reusable(pooled) {
+		pooled.channel.Close()
+		return
+	}
+
+	select {
+	case c.channels <- pooled:
+	default:
+		pooled.channel.Close()
+	}
+}
+
+// reusable indica se o canal continua aberto e pertence à conexão atual
+func (c *rabbitConnection) reusable(pooled *pooledChannel) bool {
+	c.mu.Lock()
+	generation := c.generation
+	c.mu.Unlock()
+
+	return !pooled.channel.IsClosed() && pooled.generation == generation
 }
 
+func (c *rabbitConnection) setError(err error) {
+	now := time.Now()
+
+	c.errMu.Lock()
+	c.lastError = err.Error()
+	c.lastErrorAt = &now
+	c.errMu.Unlock()
+}
+
+// health não usa c.mu, para responder mesmo enquanto uma reconexão está em andamento
+func (c *rabbitConnection) health(dedicated bool) producer_interfaces.BrokerHealth {
+	conn := c.current.Load()
+
+	c.errMu.Lock()
+	defer c.errMu.Unlock()
+
+	return producer_interfaces.BrokerHealth{
+		Dedicated:   dedicated,
+		Connected:   conn != nil && !conn.IsClosed(),
+		LastError:   c.lastError,
+		LastErrorAt: c.lastErrorAt,
+	}
+}
+
+// close encerra a conexão e os canais do pool; publicações posteriores falham
+func (c *rabbitConnection) close() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.closed = true
+	for {
+		select {
+		case pooled := <-c.channels:
+			pooled.channel.Close()
+		default:
+			if c.conn != nil && !c.conn.IsClosed() {
+				c.conn.Close()
+			}
+			return
+		}
+	}
+}
+
+// connection retorna a conexão do broker próprio de uma instância, criando-a na primeira publicação
+func (p *rabbitMQProducer) connection(brokerUrl string) *rabbitConnection {
+	p.poolMu.Lock()
+	defer p.poolMu.Unlock()
+
+	c, ok := p.pool[brokerUrl]
+	if !ok {
+		c = newRabbitConnection(nil, brokerUrl)
+		p.pool[brokerUrl] = c
+		p.janitor.Do(func() { go p.closeIdleConnections() })
+	}
+	c.lastUsed.Store(time.Now().UnixNano())
+
+	return c
+}
+
+// closeIdleConnections fecha as conexões de instâncias que pararam de publicar,
+// como as de URLs que foram trocadas ou de instâncias removidas
+func (p *rabbitMQProducer) closeIdleConnections() {
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		limit := time.Now().Add(-brokerIdleTimeout).UnixNano()
+
+		p.poolMu.Lock()
+		for brokerUrl, c := range p.pool {
+			if c.lastUsed.Load() < limit {
+				delete(p.pool, brokerUrl)
+				go c.close()
+				logger.LogInfo("Closing idle RabbitMQ connection %s", maskConnectionString(brokerUrl))
+			}
+		}
+		p.poolMu.Unlock()
+	}
+}
+
+// publishWithRetry publica e aguarda o ack do broker. Um nack ou a falta de
+// confirmação dentro de confirmTimeout contam como falha e geram nova tentativa
 func (p *rabbitMQProducer) publishWithRetry(
-	channel *amqp.Channel,
-	queueName string,
+	c *rabbitConnection,
+	exchange string,
+	routingKey string,
+	declare func(channel *amqp.Channel) error,
 	payload []byte,
 	userID string,
 ) error {
 	var err error
 	for i := 0; i < p.maxRetries; i++ {
-		err = channel.Publish(
-			"",        // exchange
-			queueName, // routing key
-			false,     // mandatory
-			false,     // immediate
-			amqp.Publishing{
-				ContentType:  "application/json",
-				Body:         payload,
-				DeliveryMode: amqp.Persistent, // Garante persistência da mensagem
-			})
+		if i > 0 {
+			time.Sleep(time.Second * time.Duration(i))
+		}
+
+		var pooled *pooledChannel
+		pooled, err = c.acquireChannel()
+		if err != nil {
+			logger.LogWarn("[%s] Falha ao obter canal (tentativa %d/%d): %v", userID, i+1, p.maxRetries, err)
+			continue
+		}
+
+		err = declare(pooled.channel)
+		if err == nil {
+			err = p.publishAndConfirm(pooled.channel, exchange, routingKey, payload)
+		}
+		c.releaseChannel(pooled)
 
 		if err == nil {
 			return nil
@@ -148,23 +359,40 @@ func (p *rabbitMQProducer) publishWithRetry(
 
 		logger.LogWarn("[%s] Falha ao publicar mensagem (tentativa %d/%d): %v",
 			userID, i+1, p.maxRetries, err)
+	}
+	return err
+}
 
-		// Se o erro for de conexão, tenta reconectar
-		if err.Error() == "Exception (504) Reason: \"channel/connection is not open\"" {
-			if err := p.ensureConnection(); err != nil {
-				continue
-			}
-
-			// Cria novo canal após reconexão
-			channel, err = p.conn.Channel()
-			if err != nil {
-				continue
-			}
-		}
+func (p *rabbitMQProducer) publishAndConfirm(channel *amqp.Channel, exchange string, routingKey string, payload []byte) error {
+	ctx, cancel := context.WithTimeout(context.Background(), confirmTimeout)
+	defer cancel()
+
+	confirmation, err := channel.PublishWithDeferredConfirmWithContext(
+		ctx,
+		exchange,   // exchange
+		routingKey, // routing key
+		false,      // mandatory
+		false,      // immediate
+		amqp.Publishing{
+			ContentType:  "application/json",
+			Body:         payload,
+			DeliveryMode: amqp.Persistent, // Garante persistência da mensagem
+		})
+	if err != nil {
+		return err
+	}
 
-		time.Sleep(time.Second * time.Duration(i+1))
+	acked, err := confirmation.WaitContext(ctx)
+	if err != nil {
+		// Sem confirmação o canal pode ter entregas pendentes; fecha para não reaproveitá-lo
+		channel.Close()
+		return fmt.Errorf("timeout aguardando confirmação do broker: %v", err)
 	}
-	return err
+	if !acked {
+		return fmt.Errorf("mensagem rejeitada pelo broker (nack)")
+	}
+
+	return nil
 }
 
 func (p *rabbitMQProducer) Produce(
@@ -172,27 +400,84 @@ func (p *rabbitMQProducer) Produce(
 	payload []byte,
 	rabbitmqEnable string,
 	userID string,
+) error {
+	if p.shared.connStr == "" {
+		return fmt.Errorf("RabbitMQ connection string is empty - check AMQP_URL configuration")
+	}
+
+	return p.produce(p.shared, queueName, payload, rabbitmqEnable, userID)
+}
+
+// ProduceToUrl publica o evento da instância no broker dela; sem URL própria usa o de AMQP_URL
+func (p *rabbitMQProducer) ProduceToUrl(brokerUrl string, queueName string, payload []byte, userID string) error {
+	if brokerUrl == "" {
+		return p.Produce(queueName, payload, "enabled", userID)
+	}
+
+	return p.produce(p.connection(brokerUrl), queueName, payload, "enabled", userID)
+}
+
+// Health retorna o estado da conexão usada pela instância; a conexão própria só existe após a primeira publicação
+func (p *rabbitMQProducer) Health(brokerUrl string) producer_interfaces.BrokerHealth {
+	if brokerUrl == "" {
+		return p.shared.health(false)
+	}
+
+	p.poolMu.Lock()
+	c, ok := p.pool[brokerUrl]
+	p.poolMu.Unlock()
+
+	if !ok {
+		return producer_interfaces.BrokerHealth{Dedicated: true}
+	}
+	return c.health(true)
+}
+
+func (p *rabbitMQProducer) produce(
+	c *rabbitConnection,
+	queueName string,
+	payload []byte,
+	rabbitmqEnable string,
+	userID string,
 ) error {
 	p.loggerWrapper.GetLogger(userID).LogInfo("[%s] RabbitMQ Producer - Starting produce for queue: %s", userID, queueName)
 
-	if p.connStr == "" {
-		return fmt.Errorf("RabbitMQ connection string is empty - check AMQP_URL configuration")
+	if rabbitmqEnable != "global" && rabbitmqEnable != "enabled" {
+		return nil
 	}
 
-	if err := p.ensureConnection(); err != nil {
-		p.loggerWrapper.GetLogger(userID).LogError("[%s] Failed to ensure RabbitMQ connection: %v", userID, err)
-		return fmt.Errorf("falha ao garantir conexão: %v", err)
+	// No modo exchange as filas são dos consumidores, que as ligam ao exchange com wildcards
+	if p.exchange != "" {
+		routingKey := exchangeRoutingKey(queueName, rabbitmqEnable)
+		declare := func(channel *amqp.Channel) error {
+			return c.declareExchange(channel, p.exchange)
+		}
+		if err := p.publishWithRetry(c, p.exchange, routingKey, declare, payload, userID); err != nil {
+			c.setError(err)
+			return fmt.Errorf("falha ao publicar mensagem após todas as tentativas: %v", err)
+		}
+
+		p.loggerWrapper.GetLogger(userID).LogInfo("[%s] Mensagem publicada com sucesso no exchange %s com routing key: %s", userID, p.exchange, routingKey)
+		return nil
 	}
 
-	channel, err := p.conn.Channel()
-	if err != nil {
-		return fmt.Errorf("falha ao abrir canal: %v", err)
+	declare := func(channel *amqp.Channel) error {
+		return c.declareQueue(channel, queueName)
 	}
-	defer channel.Close()
+	if err := p.publishWithRetry(c, "", queueName, declare, payload, userID); err != nil {
+		c.setError(err)
+		return fmt.Errorf("falha ao publicar mensagem após todas as tentativas: %v", err)
+	}
+
+	p.loggerWrapper.GetLogger(userID).LogInfo("[%s] Mensagem publicada com sucesso na fila: %s", userID, queueName)
+
+	return nil
+}
 
-	// Configura confirmação de publicação
-	if err := channel.Confirm(false); err != nil {
-		return fmt.Errorf("falha ao configurar confirms do canal: %v", err)
+// declareQueue declara a fila apenas na primeira publicação após cada conexão
+func (c *rabbitConnection) declareQueue(channel *amqp.Channel, queueName string) error {
+	if _, ok := c.declared.Load("queue:" + queueName); ok {
+		return nil
 	}
 
 	args := amqp.Table{
@@ -200,52 +485,85 @@ func (p *rabbitMQProducer) Produce(
 		"x-ha-policy":  "all", // Alta disponibilidade
 	}
 
-	if rabbitmqEnable == "global" || rabbitmqEnable == "enabled" {
-		_, err = channel.QueueDeclare(
-			queueName, // name
-			true,      // durable
-			false,     // delete when unused
-			false,     // exclusive
-			false,     // no-wait
-			args,      // arguments
-		)
-		if err != nil {
-			return fmt.Errorf("falha ao declarar fila %s: %v", queueName, err)
-		}
+	_, err := channel.QueueDeclare(
+		queueName, // name
+		true,      // durable
+		false,     // delete when unused
+		false,     // exclusive
+		false,     // no-wait
+		args,      // arguments
+	)
+	if err != nil {
+		return fmt.Errorf("falha ao declarar fila %s: %v", queueName, err)
+	}
 
-		err = p.publishWithRetry(channel, queueName, payload, userID)
-		if err != nil {
-			return fmt.Errorf("falha ao publicar mensagem após todas as tentativas: %v", err)
-		}
+	c.declared.Store("queue:"+queueName, true)
+
+	return nil
+}
+
+func (c *rabbitConnection) declareExchange(channel *amqp.Channel, exchange string) error {
+	if _, ok := c.declared.Load("exchange:" + exchange); ok {
+		return nil
+	}
 
-		p.loggerWrapper.GetLogger(userID).LogInfo("[%s] Mensagem publicada com sucesso na fila: %s", userID, queueName)
+	err := channel.ExchangeDeclare(
+		exchange, // name
+		"topic",  // type
+		true,     // durable
+		false,    // auto-deleted
+		false,    // internal
+		false,    // no-wait
+		nil,      // arguments
+	)
+	if err != nil {
+		return fmt.Errorf("falha ao declarar exchange %s: %v", exchange, err)
 	}
 
+	c.declared.Store("exchange:"+exchange, true)
+
 	return nil
 }
 
-// CreateGlobalQueues cria todas as filas globais no startup da aplicação
+// exchangeRoutingKey monta a routing key no formato instanceId.evento; eventos globais
+// usam o prefixo "global" para que possam ser ligados separadamente dos eventos por instância
+func exchangeRoutingKey(queueName string, rabbitmqEnable string) string {
+	if rabbitmqEnable == "global" {
+		return "global." + queueName
+	}
+
+	return queueName
+}
+
+// CreateGlobalQueues cria todas as filas globais no startup da aplicação.
+// No modo exchange apenas declara o exchange, pois as filas são criadas pelos consumidores
 func (p *rabbitMQProducer) CreateGlobalQueues() error {
+	if p.exchange != "" {
+		pooled, err := p.shared.acquireChannel()
+		if err != nil {
+			return fmt.Errorf("failed to acquire channel: %v", err)
+		}
+		defer p.shared.releaseChannel(pooled)
+
+		if err := p.shared.declareExchange(pooled.channel, p.exchange); err != nil {
+			return err
+		}
+
+		p.loggerWrapper.GetLogger("system").LogInfo("Topic exchange declared: %s", p.exchange)
+		return nil
+	}
+
 	if !p.amqpGlobalEnabled {
 		return nil
 	}
 
 	p.loggerWrapper.GetLogger("system").LogInfo("Creating global queues for enabled events")
 
-	if err := p.ensureConnection(); err != nil {
-		return fmt.Errorf("failed to ensure connection: %v", err)
-	}
-
-	channel, err := p.conn.Channel()
+	pooled, err := p.shared.acquireChannel()
 	if err != nil {
-		return fmt.Errorf("failed to open channel: %v", err)
-	}
-	defer channel.Close()
-
-	args := amqp.Table{
-		"x-queue-type": "quorum",
-		"x-ha-policy":  "all", // Alta disponibilidade
+		return fmt.Errorf("failed to acquire channel: %v", err)
 	}
+	defer p.shared.releaseChannel(pooled)
 
 	createdQueues := 0
 
@@ -255,16 +573,9 @@ func (p *rabbitMQProducer) CreateGlobalQueues() error {
 
 		// Cria filas diretas para eventos específicos
 		for _, eventName := range p.amqpSpecificEvents {
-			queueName := strings.ToLower(eventName)
-
-			_, err = channel.QueueDeclare(
-				queueName, // name
-				true,      // durable
-				false,     // delete when unused
-				false,     // exclusive
-				false,     // no-wait
-				args,      // arguments
-			)
+			queueName := event_types.QueueOf(eventName)
+
+			err = p.shared.declareQueue(pooled.channel, queueName)
 			if err != nil {
 				p.loggerWrapper.GetLogger("system").LogError("Failed to create specific queue %s: %v", queueName, err)
 				return fmt.Errorf("failed to create specific queue %s: %v", queueName, err)
@@ -275,41 +586,16 @@ func (p *rabbitMQProducer) CreateGlobalQueues() error {
 	} else {
 		p.loggerWrapper.GetLogger("system").LogInfo("Using AMQP_GLOBAL_EVENTS (fallback mode)")
 
-		// Mapeia eventos globais para os eventos originais que precisam de filas (modo antigo)
-		eventMap := map[string][]string{
-			"MESSAGE":       {"message"},
-			"SEND_MESSAGE":  {"sendmessage"},
-			"READ_RECEIPT":  {"receipt"},
-			"PRESENCE":      {"presence"},
-			"HISTORY_SYNC":  {"historysync"},
-			"CHAT_PRESENCE": {"chatpresence", "archive"},
-			"CALL":          {"calloffer", "callaccept", "callterminate", "calloffernotice", "callrelaylatency"},
-			"CONNECTION":    {"connected", "pairsuccess", "temporaryban", "loggedout", "connectfailure", "disconnected"},
-			"LABEL":         {"labeledit", "labelassociationchat", "labelassociationmessage"},
-			"CONTACT":       {"contact", "pushname"},
-			"GROUP":         {"groupinfo", "joinedgroup"},
-			"NEWSLETTER":    {"newsletterjoin", "newsletterleave"},
-			"QRCODE":        {"qrcode", "qrtimeout", "qrsuccess"},
-		}
-
+		// Cria uma fila para cada evento dos grupos habilitados, conforme o registry de eventos
 		for _, globalEvent := range p.amqpGlobalEvents {
-			if queueNames, exists := eventMap[globalEvent]; exists {
-				for _, queueName := range queueNames {
-					_, err = channel.QueueDeclare(
-						queueName, // name
-						true,      // durable
-						false,     // delete when unused
-						false,     // exclusive
-						false,     // no-wait
-						args,      // arguments
-					)
-					if err != nil {
-						p.loggerWrapper.GetLogger("system").LogError("Failed to create global queue %s: %v", queueName, err)
-						return fmt.Errorf("failed to create global queue %s: %v", queueName, err)
-					}
-					p.loggerWrapper.GetLogger("system").LogInfo("Global queue created: %s", queueName)
-					createdQueues++
+			for _, event := range event_types.EventsOf(globalEvent) {
+				err = p.shared.declareQueue(pooled.channel, event.Queue)
+				if err != nil {
+					p.loggerWrapper.GetLogger("system").LogError("Failed to create global queue %s: %v", event.Queue, err)
+					return fmt.Errorf("failed to create global queue %s: %v", event.Queue, err)
 				}
+				p.loggerWrapper.GetLogger("system").LogInfo("Global queue created: %s", event.Queue)
+				createdQueues++
 			}
 		}
 	}
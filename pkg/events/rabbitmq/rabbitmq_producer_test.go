@@ -0,0 +1,65 @@
+package rabbitmq_producer
+
+import (
+	"testing"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+func TestExchangeRoutingKey(t *testing.T) {
+	tests := []struct {
+		name           string
+		queueName      string
+		rabbitmqEnable string
+		want           string
+	}{
+		{"global event", "message", "global", "global.message"},
+		{"instance event", "a1b2c3.message", "enabled", "a1b2c3.message"},
+		{"legacy enabled flag", "a1b2c3.message", "true", "a1b2c3.message"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := exchangeRoutingKey(tt.queueName, tt.rabbitmqEnable); got != tt.want {
+				t.Errorf("exchangeRoutingKey(%q, %q) = %q, want %q", tt.queueName, tt.rabbitmqEnable, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReusableChannel(t *testing.T) {
+	tests := []struct {
+		name       string
+		generation uint64
+		reconnects uint64
+		want       bool
+	}{
+		{"channel from current connection", 0, 0, true},
+		{"channel from before a reconnect", 0, 1, false},
+		{"channel opened after the reconnect", 2, 2, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newRabbitConnection(nil, "")
+			c.generation += tt.reconnects
+
+			pooled := &pooledChannel{channel: &amqp.Channel{}, generation: tt.generation}
+			if got := c.reusable(pooled); got != tt.want {
+				t.Errorf("reusable() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReleaseChannelKeepsCurrentGeneration(t *testing.T) {
+	c := newRabbitConnection(nil, "")
+	c.generation = 3
+
+	pooled := &pooledChannel{channel: &amqp.Channel{}, generation: 3}
+	c.releaseChannel(pooled)
+
+	if len(c.channels) != 1 || <-c.channels != pooled {
+		t.Errorf("releaseChannel() should return a channel of the current generation to the pool")
+	}
+}
@@ -0,0 +1,135 @@
+package redis_producer
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	producer_interfaces "github.com/EvolutionAPI/evolution-go/pkg/events/interfaces"
+	logger_wrapper "github.com/EvolutionAPI/evolution-go/pkg/logger"
+	"github.com/gomessguii/logger"
+	"github.com/redis/go-redis/v9"
+)
+
+const writeTimeout = 5 * time.Second
+
+type redisProducer struct {
+	client        *redis.Client
+	streamPrefix  string
+	maxLen        int64
+	loggerWrapper *logger_wrapper.LoggerManager
+}
+
+func NewRedisProducer(
+	url string,
+	streamPrefix string,
+	maxLen int64,
+	loggerWrapper *logger_wrapper.LoggerManager,
+) producer_interfaces.Producer {
+	if url == "" {
+		return &redisProducer{
+			client:        nil,
+			loggerWrapper: loggerWrapper,
+		}
+	}
+
+	options, err := redis.ParseURL(url)
+	if err != nil {
+		logger.LogError("Failed to parse Redis URL: %v", err)
+		return &redisProducer{
+			client:        nil,
+			loggerWrapper: loggerWrapper,
+		}
+	}
+
+	// O cliente reconecta sozinho; uma falha aqui só indica que o Redis ainda não está disponível
+	client := redis.NewClient(options)
+	ctx, cancel := context.WithTimeout(context.Background(), writeTimeout)
+	defer cancel()
+	if err := client.Ping(ctx).Err(); err != nil {
+		logger.LogError("Failed to connect to Redis: %v", err)
+	}
+
+	return &redisProducer{
+		client:        client,
+		streamPrefix:  streamPrefix,
+		maxLen:        maxLen,
+		loggerWrapper: loggerWrapper,
+	}
+}
+
+func (p *redisProducer) Produce(
+	queueName string,
+	payload []byte,
+	redisEnable string,
+	userID string,
+) error {
+	if p.client == nil {
+		p.loggerWrapper.GetLogger(userID).LogWarn("[%s] Redis client is nil", userID)
+		return nil
+	}
+
+	var stream, event string
+	switch redisEnable {
+	case "global":
+		event = queueName
+		stream = globalStream(p.streamPrefix, event)
+	case "enabled", "true":
+		event = queueEvent(queueName)
+		stream = instanceStream(p.streamPrefix, userID)
+	default:
+		return nil
+	}
+
+	// O ID "*" deixa o Redis gerar IDs crescentes, compatíveis com XREADGROUP e XACK.
+	// O MAXLEN aproximado mantém o stream limitado sem custo extra a cada XADD
+	args := &redis.XAddArgs{
+		Stream: stream,
+		ID:     "*",
+		Values: map[string]interface{}{
+			"event":      event,
+			"instanceId": userID,
+			"payload":    payload,
+		},
+	}
+	if p.maxLen > 0 {
+		args.MaxLen = p.maxLen
+		args.Approx = true
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), writeTimeout)
+	defer cancel()
+
+	id, err := p.client.XAdd(ctx, args).Result()
+	if err != nil {
+		p.loggerWrapper.GetLogger(userID).LogError("[%s] Failed to add event to Redis stream %s: %v", userID, stream, err)
+		return err
+	}
+
+	p.loggerWrapper.GetLogger(userID).LogInfo("[%s] Event added to Redis stream %s with id %s", userID, stream, id)
+
+	return nil
+}
+
+// CreateGlobalQueues não faz nada para Redis producer pois os streams são criados no primeiro XADD
+func (p *redisProducer) CreateGlobalQueues() error {
+	return nil
+}
+
+func instanceStream(prefix string, instanceId string) string {
+	return fmt.Sprintf("%s:%s", prefix, instanceId)
+}
+
+func globalStream(prefix string, event string) string {
+	return fmt.Sprintf("%s:global:%s", prefix, event)
+}
+
+// queueEvent extrai o nome do evento da fila no formato instanceId.evento
+func queueEvent(queueName string) string {
+	if _, event, found := strings.Cut(queueName, "."); found {
+		return event
+	}
+
+	return queueName
+}
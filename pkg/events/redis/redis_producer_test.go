@@ -0,0 +1,70 @@
+package redis_producer
+
+import (
+	"testing"
+)
+
+func TestStreamNames(t *testing.T) {
+	tests := []struct {
+		name     string
+		stream   string
+		expected string
+	}{
+		{
+			name:     "Instance stream uses prefix and instance id",
+			stream:   instanceStream("evolution", "instance-id"),
+			expected: "evolution:instance-id",
+		},
+		{
+			name:     "Global stream is split by event",
+			stream:   globalStream("evolution", "message"),
+			expected: "evolution:global:message",
+		},
+		{
+			name:     "Custom prefix",
+			stream:   globalStream("prod:wa", "connection_update"),
+			expected: "prod:wa:global:connection_update",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.stream != tt.expected {
+				t.Errorf("Expected stream %q, but got %q", tt.expected, tt.stream)
+			}
+		})
+	}
+}
+
+func TestQueueEvent(t *testing.T) {
+	tests := []struct {
+		name      string
+		queueName string
+		expected  string
+	}{
+		{
+			name:      "Instance queue keeps only the event",
+			queueName: "instance-id.message",
+			expected:  "message",
+		},
+		{
+			name:      "Event with dots keeps everything after the instance",
+			queueName: "instance-id.group.update",
+			expected:  "group.update",
+		},
+		{
+			name:      "Queue without instance is returned as is",
+			queueName: "message",
+			expected:  "message",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := queueEvent(tt.queueName)
+			if result != tt.expected {
+				t.Errorf("Expected event %q, but got %q", tt.expected, result)
+			}
+		})
+	}
+}
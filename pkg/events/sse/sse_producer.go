@@ -0,0 +1,199 @@
+package sse_producer
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	logger_wrapper "github.com/EvolutionAPI/evolution-go/pkg/logger"
+	"github.com/gomessguii/logger"
+)
+
+const (
+	subscriberBuffer  = 256
+	keepAliveInterval = 25 * time.Second
+)
+
+// subscriber é uma conexão SSE aberta; instanceId vazio recebe eventos de todas as instâncias
+type subscriber struct {
+	instanceId string
+	events     chan streamEvent
+	done       chan struct{}
+	closeOnce  sync.Once
+}
+
+func (s *subscriber) close() {
+	s.closeOnce.Do(func() {
+		close(s.done)
+	})
+}
+
+type sseProducer struct {
+	enabled       bool
+	bufferSize    int
+	mu            sync.Mutex
+	nextId        uint64
+	buffers       map[string]*ring // últimos eventos por instância
+	global        *ring            // últimos eventos de todas as instâncias, para o stream admin
+	subscribers   map[*subscriber]struct{}
+	loggerWrapper *logger_wrapper.LoggerManager
+}
+
+func NewSseProducer(enabled bool, bufferSize int, loggerWrapper *logger_wrapper.LoggerManager) *sseProducer {
+	return &sseProducer{
+		enabled:       enabled,
+		bufferSize:    bufferSize,
+		buffers:       make(map[string]*ring),
+		global:        newRing(bufferSize),
+		subscribers:   make(map[*subscriber]struct{}),
+		loggerWrapper: loggerWrapper,
+	}
+}
+
+// Produce numera o evento, guarda no buffer da instância e entrega aos streams abertos.
+// A entrega não bloqueia: um stream cuja fila esteja cheia é encerrado e pode retomar com Last-Event-ID
+func (p *sseProducer) Produce(queueName string, payload []byte, instanceID string, _ string) error {
+	if !p.enabled {
+		return nil
+	}
+
+	name := strings.ToLower(queueName)
+	if _, after, found := strings.Cut(name, "."); found {
+		name = after
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.nextId++
+	event := streamEvent{
+		id:         p.nextId,
+		instanceId: instanceID,
+		name:       name,
+		data:       payload,
+	}
+
+	buffer, ok := p.buffers[instanceID]
+	if !ok {
+		buffer = newRing(p.bufferSize)
+		p.buffers[instanceID] = buffer
+	}
+	buffer.push(event)
+	p.global.push(event)
+
+	for s := range p.subscribers {
+		if s.instanceId != "" && s.instanceId != instanceID {
+			continue
+		}
+
+		select {
+		case s.events <- event:
+		default:
+			p.loggerWrapper.GetLogger(instanceID).LogWarn("[%s] SSE stream too slow, closing connection", instanceID)
+			delete(p.subscribers, s)
+			s.close()
+		}
+	}
+
+	return nil
+}
+
+// Stream mantém a resposta aberta enviando os eventos da instância (ou de todas, com instanceId vazio).
+// Com Last-Event-ID os eventos ainda presentes no buffer são reenviados antes dos novos
+func (p *sseProducer) Stream(w http.ResponseWriter, r *http.Request, instanceId string) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming not supported", http.StatusInternalServerError)
+		return
+	}
+
+	if !p.enabled {
+		http.Error(w, "SSE is disabled", http.StatusNotFound)
+		return
+	}
+
+	lastEventId := r.Header.Get("Last-Event-ID")
+	if lastEventId == "" {
+		lastEventId = r.URL.Query().Get("lastEventId")
+	}
+	lastId, _ := strconv.ParseUint(lastEventId, 10, 64)
+
+	s := &subscriber{
+		instanceId: instanceId,
+		events:     make(chan streamEvent, subscriberBuffer),
+		done:       make(chan struct{}),
+	}
+
+	// O replay e o registro acontecem sob o mesmo lock para não perder nem duplicar eventos
+	p.mu.Lock()
+	var replay []streamEvent
+	if lastEventId != "" {
+		if instanceId == "" {
+			replay = p.global.since(lastId)
+		} else if buffer, ok := p.buffers[instanceId]; ok {
+			replay = buffer.since(lastId)
+		}
+	}
+	p.subscribers[s] = struct{}{}
+	p.mu.Unlock()
+
+	defer func() {
+		p.mu.Lock()
+		delete(p.subscribers, s)
+		p.mu.Unlock()
+		s.close()
+	}()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	fmt.Fprint(w, "retry: 3000\n\n")
+	for _, event := range replay {
+		writeEvent(w, event)
+	}
+	flusher.Flush()
+
+	if instanceId == "" {
+		logger.LogInfo("Stream SSE global aberto (%d eventos reenviados)", len(replay))
+	} else {
+		p.loggerWrapper.GetLogger(instanceId).LogInfo("[%s] Stream SSE aberto (%d eventos reenviados)", instanceId, len(replay))
+	}
+
+	ticker := time.NewTicker(keepAliveInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case event := <-s.events:
+			writeEvent(w, event)
+			flusher.Flush()
+		case <-ticker.C:
+			fmt.Fprint(w, ": ping\n\n")
+			flusher.Flush()
+		case <-s.done:
+			return
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
+
+func writeEvent(w http.ResponseWriter, event streamEvent) {
+	fmt.Fprintf(w, "id: %d\nevent: %s\n", event.id, event.name)
+	for _, line := range bytes.Split(event.data, []byte("\n")) {
+		fmt.Fprintf(w, "data: %s\n", line)
+	}
+	fmt.Fprint(w, "\n")
+}
+
+// CreateGlobalQueues não faz nada para SSE producer
+func (p *sseProducer) CreateGlobalQueues() error {
+	return nil
+}
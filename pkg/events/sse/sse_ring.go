@@ -0,0 +1,48 @@
+package sse_producer
+
+// streamEvent é um evento já numerado, pronto para ser enviado ou reenviado via Last-Event-ID
+type streamEvent struct {
+	id         uint64
+	instanceId string
+	name       string
+	data       []byte
+}
+
+// ring guarda os últimos eventos em um buffer circular de tamanho fixo
+type ring struct {
+	events []streamEvent
+	start  int
+	size   int
+}
+
+func newRing(capacity int) *ring {
+	return &ring{events: make([]streamEvent, capacity)}
+}
+
+func (r *ring) push(event streamEvent) {
+	if len(r.events) == 0 {
+		return
+	}
+
+	if r.size < len(r.events) {
+		r.events[(r.start+r.size)%len(r.events)] = event
+		r.size++
+		return
+	}
+
+	r.events[r.start] = event
+	r.start = (r.start + 1) % len(r.events)
+}
+
+// since retorna, em ordem, os eventos com id maior que lastId ainda presentes no buffer
+func (r *ring) since(lastId uint64) []streamEvent {
+	var events []streamEvent
+	for i := 0; i < r.size; i++ {
+		event := r.events[(r.start+i)%len(r.events)]
+		if event.id > lastId {
+			events = append(events, event)
+		}
+	}
+
+	return events
+}
@@ -0,0 +1,44 @@
+package sse_producer
+
+import (
+	"reflect"
+	"testing"
+)
+
+func ringIds(events []streamEvent) []uint64 {
+	ids := make([]uint64, 0, len(events))
+	for _, event := range events {
+		ids = append(ids, event.id)
+	}
+	return ids
+}
+
+func TestRingSince(t *testing.T) {
+	tests := []struct {
+		name     string
+		capacity int
+		pushed   int
+		lastId   uint64
+		expected []uint64
+	}{
+		{"Partial buffer from start", 5, 3, 0, []uint64{1, 2, 3}},
+		{"Partial buffer resume", 5, 3, 2, []uint64{3}},
+		{"Wrapped buffer keeps newest", 3, 5, 0, []uint64{3, 4, 5}},
+		{"Wrapped buffer resume", 3, 5, 3, []uint64{4, 5}},
+		{"Up to date", 3, 5, 5, []uint64{}},
+		{"Zero capacity", 0, 5, 0, []uint64{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newRing(tt.capacity)
+			for i := 1; i <= tt.pushed; i++ {
+				r.push(streamEvent{id: uint64(i)})
+			}
+
+			if got := ringIds(r.since(tt.lastId)); !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("since(%d) = %v, want %v", tt.lastId, got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,129 @@
+// Package webhook_signature assina e verifica as entregas de webhook do Evolution GO.
+//
+// Cada entrega carrega dois headers:
+//
+//	X-Evolution-Timestamp: 1718035200
+//	X-Evolution-Signature: v1=5257a869e7ecebeda32affa62cdca3fa51cad7e77a0e56ff536d0ce8e108d8bd
+//
+// A assinatura é o HMAC-SHA256 (hex) de "<timestamp>.<corpo>" usando o segredo do webhook.
+// Durante a rotação de segredo o header traz uma assinatura por segredo ativo,
+// separadas por vírgula, e basta que uma delas confira.
+//
+// Para validar no receptor:
+//
+//	err := webhook_signature.Verify(
+//		[]string{os.Getenv("EVOLUTION_WEBHOOK_SECRET")},
+//		r.Header.Get(webhook_signature.TimestampHeader),
+//		r.Header.Get(webhook_signature.SignatureHeader),
+//		body,
+//		5*time.Minute,
+//	)
+package webhook_signature
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	TimestampHeader = "X-Evolution-Timestamp"
+	SignatureHeader = "X-Evolution-Signature"
+
+	// DefaultTolerance é a diferença máxima aceita entre o timestamp assinado e o relógio do receptor
+	DefaultTolerance = 5 * time.Minute
+
+	signatureScheme = "v1"
+)
+
+var (
+	ErrMissingHeaders    = errors.New("missing signature headers")
+	ErrInvalidTimestamp  = errors.New("invalid signature timestamp")
+	ErrTimestampExpired  = errors.New("signature timestamp outside tolerance")
+	ErrSignatureMismatch = errors.New("no signature matches the provided secrets")
+)
+
+// Sign calcula a assinatura hex de um corpo para o timestamp informado
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Headers monta os headers de assinatura para todos os segredos não vazios
+func Headers(secrets []string, timestamp int64, body []byte) map[string]string {
+	var signatures []string
+	for _, secret := range secrets {
+		if secret == "" {
+			continue
+		}
+		signatures = append(signatures, signatureScheme+"="+Sign(secret, timestamp, body))
+	}
+
+	if len(signatures) == 0 {
+		return nil
+	}
+
+	return map[string]string{
+		TimestampHeader: strconv.FormatInt(timestamp, 10),
+		SignatureHeader: strings.Join(signatures, ","),
+	}
+}
+
+// Verify valida os headers recebidos contra qualquer um dos segredos informados.
+// Uma tolerância zero desativa a checagem de janela de tempo.
+func Verify(secrets []string, timestampHeader string, signatureHeader string, body []byte, tolerance time.Duration) error {
+	if timestampHeader == "" || signatureHeader == "" {
+		return ErrMissingHeaders
+	}
+
+	timestamp, err := strconv.ParseInt(timestampHeader, 10, 64)
+	if err != nil {
+		return ErrInvalidTimestamp
+	}
+
+	if tolerance > 0 {
+		diff := time.Since(time.Unix(timestamp, 0))
+		if diff < 0 {
+			diff = -diff
+		}
+		if diff > tolerance {
+			return ErrTimestampExpired
+		}
+	}
+
+	for _, secret := range secrets {
+		if secret == "" {
+			continue
+		}
+		expected := Sign(secret, timestamp, body)
+
+		for _, part := range strings.Split(signatureHeader, ",") {
+			scheme, value, found := strings.Cut(strings.TrimSpace(part), "=")
+			if !found || scheme != signatureScheme {
+				continue
+			}
+			if hmac.Equal([]byte(value), []byte(expected)) {
+				return nil
+			}
+		}
+	}
+
+	return ErrSignatureMismatch
+}
+
+// GenerateSecret cria um segredo aleatório de 32 bytes em hex
+func GenerateSecret() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return "whsec_" + hex.EncodeToString(buf), nil
+}
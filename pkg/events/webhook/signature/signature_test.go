@@ -0,0 +1,111 @@
+package webhook_signature
+
+import (
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestVerify(t *testing.T) {
+	body := []byte(`{"event":"Message"}`)
+	now := time.Now().Unix()
+
+	signed := Headers([]string{"current", "previous"}, now, body)
+	old := Headers([]string{"current"}, now-int64((10*time.Minute).Seconds()), body)
+
+	tests := []struct {
+		name      string
+		secrets   []string
+		timestamp string
+		signature string
+		body      []byte
+		expectErr error
+	}{
+		{
+			name:      "Current secret matches",
+			secrets:   []string{"current"},
+			timestamp: signed[TimestampHeader],
+			signature: signed[SignatureHeader],
+			body:      body,
+			expectErr: nil,
+		},
+		{
+			name:      "Previous secret matches during rotation",
+			secrets:   []string{"previous"},
+			timestamp: signed[TimestampHeader],
+			signature: signed[SignatureHeader],
+			body:      body,
+			expectErr: nil,
+		},
+		{
+			name:      "Unknown secret",
+			secrets:   []string{"other"},
+			timestamp: signed[TimestampHeader],
+			signature: signed[SignatureHeader],
+			body:      body,
+			expectErr: ErrSignatureMismatch,
+		},
+		{
+			name:      "Tampered body",
+			secrets:   []string{"current"},
+			timestamp: signed[TimestampHeader],
+			signature: signed[SignatureHeader],
+			body:      []byte(`{"event":"Receipt"}`),
+			expectErr: ErrSignatureMismatch,
+		},
+		{
+			name:      "Expired timestamp",
+			secrets:   []string{"current"},
+			timestamp: old[TimestampHeader],
+			signature: old[SignatureHeader],
+			body:      body,
+			expectErr: ErrTimestampExpired,
+		},
+		{
+			name:      "Invalid timestamp",
+			secrets:   []string{"current"},
+			timestamp: "abc",
+			signature: signed[SignatureHeader],
+			body:      body,
+			expectErr: ErrInvalidTimestamp,
+		},
+		{
+			name:      "Missing headers",
+			secrets:   []string{"current"},
+			timestamp: "",
+			signature: "",
+			body:      body,
+			expectErr: ErrMissingHeaders,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Verify(tt.secrets, tt.timestamp, tt.signature, tt.body, DefaultTolerance)
+			if err != tt.expectErr {
+				t.Errorf("Expected error %v, but got %v", tt.expectErr, err)
+			}
+		})
+	}
+}
+
+func TestHeadersWithoutSecrets(t *testing.T) {
+	headers := Headers([]string{"", ""}, time.Now().Unix(), []byte("{}"))
+	if headers != nil {
+		t.Errorf("Expected no headers when no secret is configured, got %v", headers)
+	}
+}
+
+func TestSignIsDeterministic(t *testing.T) {
+	timestamp := int64(1718035200)
+	first := Sign("secret", timestamp, []byte("payload"))
+	second := Sign("secret", timestamp, []byte("payload"))
+
+	if first != second {
+		t.Errorf("Expected identical signatures, got %q and %q", first, second)
+	}
+
+	if first == Sign("secret", timestamp+1, []byte("payload")) {
+		t.Errorf("Expected signature to change with timestamp %s", strconv.FormatInt(timestamp+1, 10))
+	}
+}
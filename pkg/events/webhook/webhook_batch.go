@@ -0,0 +1,180 @@
+package webhook_producer
+
+import (
+	"bytes"
+	"encoding/json"
+	"time"
+
+	event_envelope "github.com/EvolutionAPI/evolution-go/pkg/events/envelope"
+	producer_interfaces "github.com/EvolutionAPI/evolution-go/pkg/events/interfaces"
+	webhook_model "github.com/EvolutionAPI/evolution-go/pkg/webhook/model"
+)
+
+// batchWindow acumula as entregas de um destino até o fim da janela ou até atingir os limites do lote
+type batchWindow struct {
+	ids     []string
+	bytes   int
+	flushAt time.Time
+}
+
+// outboxBatch é um grupo de entregas do mesmo destino enviado em um único POST
+type outboxBatch []webhook_model.WebhookOutbox
+
+// batchKey identifica o destino; entregas com segredos ou headers diferentes não podem dividir o mesmo POST
+func batchKey(instanceId string, target producer_interfaces.WebhookTarget) string {
+	key, _ := json.Marshal([]interface{}{instanceId, target.Url, target.Secret, target.PreviousSecret, target.Headers})
+	return string(key)
+}
+
+// enqueueBatched grava a entrega agendada para o fim da janela do destino, para que o worker a envie junto
+// com as demais. Ao atingir maxEvents ou maxBytes a janela é fechada e as entregas são antecipadas
+func (p *webhookProducer) enqueueBatched(outbox *webhook_model.WebhookOutbox, target producer_interfaces.WebhookTarget, userID string) error {
+	key := batchKey(userID, target)
+	maxEvents, maxBytes, interval := target.Batch.Limits()
+
+	p.batchMu.Lock()
+	window := p.batches[key]
+	if window == nil || !time.Now().Before(window.flushAt) {
+		window = &batchWindow{flushAt: time.Now().Add(interval)}
+		p.batches[key] = window
+
+		time.AfterFunc(interval, func() {
+			p.batchMu.Lock()
+			if p.batches[key] == window {
+				delete(p.batches, key)
+			}
+			p.batchMu.Unlock()
+
+			p.notifyWorker()
+		})
+	}
+	outbox.NextAttemptAt = window.flushAt
+	p.batchMu.Unlock()
+
+	if err := p.webhookRepository.EnqueueOutbox(outbox); err != nil {
+		return err
+	}
+
+	var expedite []string
+	p.batchMu.Lock()
+	if p.batches[key] == window {
+		window.ids = append(window.ids, outbox.Id)
+		window.bytes += len(outbox.Payload)
+		if len(window.ids) >= maxEvents || window.bytes >= maxBytes {
+			delete(p.batches, key)
+			expedite = window.ids
+		}
+	}
+	p.batchMu.Unlock()
+
+	if len(expedite) > 0 {
+		if err := p.webhookRepository.ExpediteOutbox(expedite, time.Now()); err != nil {
+			p.loggerWrapper.GetLogger(userID).LogError("[%s] failed to flush webhook batch - url: %s, error: %v", userID, target.Url, err)
+		}
+		p.notifyWorker()
+	}
+
+	return nil
+}
+
+// groupBatches separa as entregas que devem seguir em lote, agrupadas por destino e na ordem recebida.
+// Retentativas são sempre individuais, pois um lote só falha por inteiro. Os segredos gravados são cifrados
+// com nonce aleatório, então a chave do destino vem de targetKey, que compara os valores decifrados
+func groupBatches(outboxes []webhook_model.WebhookOutbox, targetKey func(webhook_model.WebhookOutbox) string) ([][]outboxBatch, []webhook_model.WebhookOutbox) {
+	var single []webhook_model.WebhookOutbox
+	var keys []string
+	groups := make(map[string][]outboxBatch)
+	sizes := make(map[string]int)
+
+	for _, outbox := range outboxes {
+		if !outbox.Batch.Enabled() || outbox.Attempts > 0 {
+			single = append(single, outbox)
+			continue
+		}
+
+		key := targetKey(outbox)
+		maxEvents, maxBytes, _ := outbox.Batch.Limits()
+
+		batches, ok := groups[key]
+		if !ok {
+			keys = append(keys, key)
+		}
+
+		last := len(batches) - 1
+		if last < 0 || len(batches[last]) >= maxEvents || sizes[key]+len(outbox.Payload) > maxBytes {
+			batches = append(batches, nil)
+			last++
+			sizes[key] = 0
+		}
+		batches[last] = append(batches[last], outbox)
+		sizes[key] += len(outbox.Payload)
+		groups[key] = batches
+	}
+
+	result := make([][]outboxBatch, 0, len(keys))
+	for _, key := range keys {
+		result = append(result, groups[key])
+	}
+
+	return result, single
+}
+
+// outboxBatchKey identifica o destino de uma entrega gravada. Se os segredos não puderem ser decifrados a
+// entrega fica sozinha no lote, e o erro é registrado no envio
+func (p *webhookProducer) outboxBatchKey(outbox webhook_model.WebhookOutbox) string {
+	target, err := p.outboxTarget(outbox)
+	if err != nil {
+		return outbox.Id
+	}
+
+	return batchKey(outbox.InstanceId, target)
+}
+
+// deliverBatch envia o lote como um array JSON. Se o destino recusar, cada entrega segue individualmente,
+// na mesma ordem, com as retentativas e o dead letter de sempre
+func (p *webhookProducer) deliverBatch(batch outboxBatch) {
+	first := batch[0]
+	userID := first.InstanceId
+
+	var body bytes.Buffer
+	body.WriteByte('[')
+	for i, outbox := range batch {
+		if i > 0 {
+			body.WriteByte(',')
+		}
+		body.Write(outbox.Payload)
+	}
+	body.WriteByte(']')
+
+	// Os eventos de um destino seguem o mesmo formato; em CloudEvents o array usa o Content-Type do modo batch
+	var headers map[string]string
+	if event_envelope.IsCloudEvent(first.Payload) {
+		headers = map[string]string{"Content-Type": event_envelope.CloudEventsBatchContentType}
+	}
+
+	startedAt := time.Now()
+	target, err := p.outboxTarget(first)
+	var responseBody []byte
+	var statusCode int
+	if err == nil {
+		err, responseBody, statusCode = p.post(target, body.Bytes(), headers)
+	}
+	if err != nil {
+		p.loggerWrapper.GetLogger(userID).LogWarn("[%s] webhook batch failed, falling back to single delivery - url: %s, events: %d, error: %v", userID, first.Url, len(batch), err)
+		for _, outbox := range batch {
+			p.deliver(outbox)
+		}
+		return
+	}
+
+	latency := time.Since(startedAt)
+	p.loggerWrapper.GetLogger(userID).LogInfo("[%s] webhook batch sent successfully - url: %s, events: %d, status: %d", userID, first.Url, len(batch), statusCode)
+
+	for _, outbox := range batch {
+		outbox.Attempts++
+		p.recordDelivery(outbox, latency, statusCode, responseBody, nil)
+		if err := p.webhookRepository.DeleteOutbox(outbox.Id); err != nil {
+			p.loggerWrapper.GetLogger(userID).LogError("[%s] failed to remove delivered webhook %s from outbox: %v", userID, outbox.Id, err)
+		}
+	}
+}
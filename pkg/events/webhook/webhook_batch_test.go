@@ -0,0 +1,76 @@
+package webhook_producer
+
+import (
+	"reflect"
+	"testing"
+
+	webhook_model "github.com/EvolutionAPI/evolution-go/pkg/webhook/model"
+)
+
+func TestGroupBatches(t *testing.T) {
+	batch := &webhook_model.WebhookBatch{MaxEvents: 2, MaxBytes: 10}
+
+	outbox := func(id string, url string, payload string, batch *webhook_model.WebhookBatch, attempts int) webhook_model.WebhookOutbox {
+		return webhook_model.WebhookOutbox{Id: id, InstanceId: "inst", Url: url, Payload: []byte(payload), Batch: batch, Attempts: attempts}
+	}
+
+	tests := []struct {
+		name    string
+		outbox  []webhook_model.WebhookOutbox
+		batches [][][]string
+		single  []string
+	}{
+		{
+			name:    "splits by max events keeping order",
+			outbox:  []webhook_model.WebhookOutbox{outbox("1", "a", "{}", batch, 0), outbox("2", "a", "{}", batch, 0), outbox("3", "a", "{}", batch, 0)},
+			batches: [][][]string{{{"1", "2"}, {"3"}}},
+		},
+		{
+			name:    "splits by max bytes",
+			outbox:  []webhook_model.WebhookOutbox{outbox("1", "a", `{"a":"1"}`, batch, 0), outbox("2", "a", "{}", batch, 0)},
+			batches: [][][]string{{{"1"}, {"2"}}},
+		},
+		{
+			name:    "groups by target",
+			outbox:  []webhook_model.WebhookOutbox{outbox("1", "a", "{}", batch, 0), outbox("2", "b", "{}", batch, 0), outbox("3", "a", "{}", batch, 0)},
+			batches: [][][]string{{{"1", "3"}}, {{"2"}}},
+		},
+		{
+			name:    "retries and targets without batch are single",
+			outbox:  []webhook_model.WebhookOutbox{outbox("1", "a", "{}", batch, 1), outbox("2", "a", "{}", nil, 0), outbox("3", "a", "{}", &webhook_model.WebhookBatch{MaxEvents: 1}, 0)},
+			batches: [][][]string{},
+			single:  []string{"1", "2", "3"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			groups, single := groupBatches(tt.outbox, func(outbox webhook_model.WebhookOutbox) string { return outbox.Url })
+
+			gotBatches := [][][]string{}
+			for _, group := range groups {
+				var gotGroup [][]string
+				for _, batch := range group {
+					var ids []string
+					for _, outbox := range batch {
+						ids = append(ids, outbox.Id)
+					}
+					gotGroup = append(gotGroup, ids)
+				}
+				gotBatches = append(gotBatches, gotGroup)
+			}
+
+			var gotSingle []string
+			for _, outbox := range single {
+				gotSingle = append(gotSingle, outbox.Id)
+			}
+
+			if !reflect.DeepEqual(gotBatches, tt.batches) {
+				t.Errorf("batches = %v, want %v", gotBatches, tt.batches)
+			}
+			if !reflect.DeepEqual(gotSingle, tt.single) {
+				t.Errorf("single = %v, want %v", gotSingle, tt.single)
+			}
+		})
+	}
+}
@@ -0,0 +1,230 @@
+package webhook_producer
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	webhook_model "github.com/EvolutionAPI/evolution-go/pkg/webhook/model"
+)
+
+const (
+	outboxPollInterval = 5 * time.Second
+	outboxBatchSize    = 100
+	outboxLockDuration = 2 * time.Minute
+	deliveryTimeout    = 30 * time.Second
+	retentionInterval  = time.Hour
+
+	responseSnippetSize = 1024
+)
+
+// runOutboxWorker processa as entregas pendentes periodicamente ou assim que uma nova entrega é gravada.
+// Como o estado fica no banco, entregas interrompidas por um restart são retomadas na próxima execução
+func (p *webhookProducer) runOutboxWorker() {
+	ticker := time.NewTicker(outboxPollInterval)
+	defer ticker.Stop()
+
+	for {
+		p.processOutbox()
+
+		select {
+		case <-ticker.C:
+		case <-p.wakeup:
+		}
+	}
+}
+
+func (p *webhookProducer) notifyWorker() {
+	select {
+	case p.wakeup <- struct{}{}:
+	default:
+	}
+}
+
+func (p *webhookProducer) processOutbox() {
+	now := time.Now()
+
+	pending, err := p.webhookRepository.GetDueOutbox(now, outboxBatchSize)
+	if err != nil {
+		p.loggerWrapper.GetLogger("system").LogError("Failed to load webhook outbox: %v", err)
+		return
+	}
+
+	var claimedOutbox []webhook_model.WebhookOutbox
+	for _, outbox := range pending {
+		claimed, err := p.webhookRepository.ClaimOutbox(outbox.Id, now, now.Add(outboxLockDuration))
+		if err != nil {
+			p.loggerWrapper.GetLogger(outbox.InstanceId).LogError("[%s] failed to claim webhook delivery %s: %v", outbox.InstanceId, outbox.Id, err)
+			continue
+		}
+		if !claimed {
+			continue
+		}
+
+		claimedOutbox = append(claimedOutbox, outbox)
+	}
+
+	if len(claimedOutbox) == 0 {
+		return
+	}
+
+	// Os lotes de um destino seguem em sequência e, com o fallback individual, podem levar mais que
+	// outboxLockDuration. O lock é renovado até o fim dos envios para que outra réplica não os repita
+	ids := make([]string, 0, len(claimedOutbox))
+	for _, outbox := range claimedOutbox {
+		ids = append(ids, outbox.Id)
+	}
+	done := make(chan struct{})
+	defer close(done)
+	go p.renewLocks(ids, done)
+
+	groups, single := groupBatches(claimedOutbox, p.outboxBatchKey)
+
+	var wg sync.WaitGroup
+	for _, outbox := range single {
+		wg.Add(1)
+		go func(outbox webhook_model.WebhookOutbox) {
+			defer wg.Done()
+			p.deliver(outbox)
+		}(outbox)
+	}
+
+	// Os lotes de um mesmo destino seguem em sequência para preservar a ordem dos eventos
+	for _, batches := range groups {
+		wg.Add(1)
+		go func(batches []outboxBatch) {
+			defer wg.Done()
+			for _, batch := range batches {
+				p.deliverBatch(batch)
+			}
+		}(batches)
+	}
+	wg.Wait()
+}
+
+func (p *webhookProducer) renewLocks(ids []string, done <-chan struct{}) {
+	ticker := time.NewTicker(outboxLockDuration / 2)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+			if err := p.webhookRepository.RenewOutboxLock(ids, time.Now().Add(outboxLockDuration)); err != nil {
+				p.loggerWrapper.GetLogger("system").LogError("Failed to renew webhook outbox lock: %v", err)
+			}
+		}
+	}
+}
+
+func (p *webhookProducer) deliver(outbox webhook_model.WebhookOutbox) {
+	userID := outbox.InstanceId
+
+	outbox.Attempts++
+	startedAt := time.Now()
+	target, err := p.outboxTarget(outbox)
+	var responseBody []byte
+	var statusCode int
+	if err == nil {
+		err, responseBody, statusCode = p.sendWebhook(target, outbox.Payload, userID)
+	}
+	p.recordDelivery(outbox, time.Since(startedAt), statusCode, responseBody, err)
+	if err == nil {
+		p.loggerWrapper.GetLogger(userID).LogInfo("[%s] webhook sent successfully - url: %s, status: %d, response: %s", userID, outbox.Url, statusCode, string(responseBody))
+		if err := p.webhookRepository.DeleteOutbox(outbox.Id); err != nil {
+			p.loggerWrapper.GetLogger(userID).LogError("[%s] failed to remove delivered webhook %s from outbox: %v", userID, outbox.Id, err)
+		}
+		return
+	}
+
+	outbox.LastError = err.Error()
+	outbox.LastStatusCode = statusCode
+
+	if outbox.Attempts >= p.maxAttempts {
+		p.loggerWrapper.GetLogger(userID).LogError("[%s] webhook failed after maximum retries, moving to dead letter - url: %s, attempts: %d, error: %v", userID, outbox.Url, outbox.Attempts, err)
+		if err := p.webhookRepository.MoveToDeadLetter(outbox); err != nil {
+			p.loggerWrapper.GetLogger(userID).LogError("[%s] failed to move webhook %s to dead letter: %v", userID, outbox.Id, err)
+		}
+		return
+	}
+
+	delay := retryDelay(outbox.Attempts, p.retryBase, p.retryMax)
+	outbox.NextAttemptAt = time.Now().Add(delay)
+
+	p.loggerWrapper.GetLogger(userID).LogWarn("[%s] webhook failed - url: %s, attempt: %d, next attempt in: %s, error: %v", userID, outbox.Url, outbox.Attempts, delay, err)
+	if err := p.webhookRepository.RescheduleOutbox(outbox); err != nil {
+		p.loggerWrapper.GetLogger(userID).LogError("[%s] failed to reschedule webhook %s: %v", userID, outbox.Id, err)
+	}
+}
+
+// runDeliveryRetention remove periodicamente as tentativas mais antigas que WEBHOOK_LOG_RETENTION_HOURS
+func (p *webhookProducer) runDeliveryRetention() {
+	ticker := time.NewTicker(retentionInterval)
+	defer ticker.Stop()
+
+	for {
+		deleted, err := p.webhookRepository.DeleteDeliveriesOlderThan(time.Now().Add(-p.deliveryRetention))
+		if err != nil {
+			p.loggerWrapper.GetLogger("system").LogError("Failed to apply webhook delivery retention: %v", err)
+		} else if deleted > 0 {
+			p.loggerWrapper.GetLogger("system").LogInfo("Webhook delivery retention removed %d deliveries", deleted)
+		}
+
+		<-ticker.C
+	}
+}
+
+// recordDelivery grava o resultado da tentativa no log de entregas consultado pela API da instância
+func (p *webhookProducer) recordDelivery(outbox webhook_model.WebhookOutbox, latency time.Duration, statusCode int, responseBody []byte, sendErr error) {
+	delivery := &webhook_model.WebhookDelivery{
+		InstanceId:      outbox.InstanceId,
+		OutboxId:        outbox.Id,
+		Event:           outbox.Event,
+		Url:             outbox.Url,
+		Attempt:         outbox.Attempts,
+		Status:          webhook_model.DeliveryStatusSuccess,
+		StatusCode:      statusCode,
+		LatencyMs:       latency.Milliseconds(),
+		ResponseSnippet: responseSnippet(responseBody),
+	}
+
+	if sendErr != nil {
+		delivery.Status = webhook_model.DeliveryStatusFailed
+		delivery.Error = sendErr.Error()
+	}
+
+	if err := p.webhookRepository.InsertDelivery(delivery); err != nil {
+		p.loggerWrapper.GetLogger(outbox.InstanceId).LogError("[%s] failed to record webhook delivery %s: %v", outbox.InstanceId, outbox.Id, err)
+	}
+}
+
+// responseSnippet limita o corpo da resposta armazenado, cortando sem quebrar caracteres UTF-8
+func responseSnippet(body []byte) string {
+	if len(body) <= responseSnippetSize {
+		return strings.ToValidUTF8(string(body), "")
+	}
+
+	return strings.ToValidUTF8(string(body[:responseSnippetSize]), "")
+}
+
+// retryDelay calcula o backoff exponencial (base * 2^(tentativa-1)) limitado ao máximo configurado
+func retryDelay(attempt int, base time.Duration, max time.Duration) time.Duration {
+	if attempt < 1 {
+		attempt = 1
+	}
+
+	delay := base
+	for i := 1; i < attempt; i++ {
+		delay *= 2
+		if delay >= max || delay <= 0 {
+			return max
+		}
+	}
+
+	if delay > max {
+		return max
+	}
+
+	return delay
+}
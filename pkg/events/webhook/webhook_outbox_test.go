@@ -0,0 +1,84 @@
+package webhook_producer
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	producer_interfaces "github.com/EvolutionAPI/evolution-go/pkg/events/interfaces"
+	webhook_model "github.com/EvolutionAPI/evolution-go/pkg/webhook/model"
+)
+
+func TestRetryDelay(t *testing.T) {
+	tests := []struct {
+		name     string
+		attempt  int
+		expected time.Duration
+	}{
+		{
+			name:     "First attempt uses base delay",
+			attempt:  1,
+			expected: 30 * time.Second,
+		},
+		{
+			name:     "Second attempt doubles the delay",
+			attempt:  2,
+			expected: time.Minute,
+		},
+		{
+			name:     "Fourth attempt",
+			attempt:  4,
+			expected: 4 * time.Minute,
+		},
+		{
+			name:     "Delay is capped at the maximum",
+			attempt:  10,
+			expected: time.Hour,
+		},
+		{
+			name:     "Large attempt does not overflow",
+			attempt:  200,
+			expected: time.Hour,
+		},
+		{
+			name:     "Invalid attempt falls back to base delay",
+			attempt:  0,
+			expected: 30 * time.Second,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := retryDelay(tt.attempt, 30*time.Second, time.Hour)
+			if result != tt.expected {
+				t.Errorf("For attempt %d, expected %s, but got %s", tt.attempt, tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestOutboxSecrets(t *testing.T) {
+	p := &webhookProducer{encryptionKey: "key"}
+	target := producer_interfaces.WebhookTarget{Url: "http://example.com", Secret: "current", PreviousSecret: "previous"}
+
+	outbox := webhook_model.WebhookOutbox{Url: target.Url}
+	if err := p.sealSecrets(&outbox, target); err != nil {
+		t.Fatalf("sealSecrets() error = %v", err)
+	}
+	if strings.Contains(outbox.Secret, "current") || strings.Contains(outbox.PreviousSecret, "previous") {
+		t.Errorf("sealSecrets() stored plaintext: %q, %q", outbox.Secret, outbox.PreviousSecret)
+	}
+
+	opened, err := p.outboxTarget(outbox)
+	if err != nil {
+		t.Fatalf("outboxTarget() error = %v", err)
+	}
+	if opened.Secret != "current" || opened.PreviousSecret != "previous" {
+		t.Errorf("outboxTarget() secrets = %q, %q", opened.Secret, opened.PreviousSecret)
+	}
+
+	p.encryptionKey = "other"
+	if _, err := p.outboxTarget(outbox); err == nil {
+		t.Error("outboxTarget() with another key should fail")
+	}
+}
@@ -7,25 +7,73 @@ import (
 	"io"
 	"net/http"
 	"strings"
+	"sync"
 	"time"
 
+	event_envelope "github.com/EvolutionAPI/evolution-go/pkg/events/envelope"
 	producer_interfaces "github.com/EvolutionAPI/evolution-go/pkg/events/interfaces"
+	webhook_signature "github.com/EvolutionAPI/evolution-go/pkg/events/webhook/signature"
+	"github.com/EvolutionAPI/evolution-go/pkg/internal/secret_box"
 	logger_wrapper "github.com/EvolutionAPI/evolution-go/pkg/logger"
+	webhook_model "github.com/EvolutionAPI/evolution-go/pkg/webhook/model"
+	webhook_repository "github.com/EvolutionAPI/evolution-go/pkg/webhook/repository"
 )
 
 type webhookProducer struct {
-	url           string
-	loggerWrapper *logger_wrapper.LoggerManager
+	url               string
+	secret            string
+	previousSecret    string
+	encryptionKey     string // cifra os segredos gravados no outbox e no dead letter
+	cloudEventsBinary bool
+	webhookRepository webhook_repository.WebhookRepository
+	maxAttempts       int
+	retryBase         time.Duration
+	retryMax          time.Duration
+	deliveryRetention time.Duration
+	httpClient        *http.Client
+	wakeup            chan struct{}
+	batchMu           sync.Mutex
+	batches           map[string]*batchWindow // janelas abertas por destino com agrupamento
+	loggerWrapper     *logger_wrapper.LoggerManager
 }
 
 func NewWebhookProducer(
 	url string,
+	secret string,
+	previousSecret string,
+	encryptionKey string,
+	cloudEventsBinary bool,
+	webhookRepository webhook_repository.WebhookRepository,
+	maxAttempts int,
+	retryBase time.Duration,
+	retryMax time.Duration,
+	deliveryRetention time.Duration,
 	loggerWrapper *logger_wrapper.LoggerManager,
-) producer_interfaces.Producer {
-	return &webhookProducer{
-		url:           url,
-		loggerWrapper: loggerWrapper,
+) producer_interfaces.WebhookProducer {
+	p := &webhookProducer{
+		url:               url,
+		secret:            secret,
+		previousSecret:    previousSecret,
+		encryptionKey:     encryptionKey,
+		cloudEventsBinary: cloudEventsBinary,
+		webhookRepository: webhookRepository,
+		maxAttempts:       maxAttempts,
+		retryBase:         retryBase,
+		retryMax:          retryMax,
+		deliveryRetention: deliveryRetention,
+		httpClient:        &http.Client{Timeout: deliveryTimeout},
+		wakeup:            make(chan struct{}, 1),
+		batches:           make(map[string]*batchWindow),
+		loggerWrapper:     loggerWrapper,
 	}
+
+	go p.runOutboxWorker()
+
+	if deliveryRetention > 0 {
+		go p.runDeliveryRetention()
+	}
+
+	return p
 }
 
 func (p *webhookProducer) Produce(
@@ -34,46 +82,187 @@ func (p *webhookProducer) Produce(
 	webhookUrl string,
 	userID string,
 ) error {
-	splitQueue := strings.Split(queueName, ".")
+	return p.ProduceToTarget(queueName, payload, producer_interfaces.WebhookTarget{Url: webhookUrl}, userID)
+}
 
-	if len(splitQueue) < 2 {
+// ProduceToTarget grava no outbox uma entrega para o webhook global e outra para o destino da instância.
+// O envio acontece no worker, que assina cada tentativa com os segredos correspondentes
+func (p *webhookProducer) ProduceToTarget(
+	queueName string,
+	payload []byte,
+	target producer_interfaces.WebhookTarget,
+	userID string,
+) error {
+	event, ok := queueEvent(queueName)
+	if !ok {
 		return nil
 	}
 
+	var errs []error
 	if p.url != "" {
-		go p.sendWebhookWithRetry(p.url, payload, 5, 30*time.Second, userID)
+		global := producer_interfaces.WebhookTarget{
+			Url:            p.url,
+			Secret:         p.secret,
+			PreviousSecret: p.previousSecret,
+		}
+		if err := p.enqueue(global, event, payload, userID); err != nil {
+			errs = append(errs, err)
+		}
 	}
-	if webhookUrl != "" {
-		go p.sendWebhookWithRetry(webhookUrl, payload, 5, 30*time.Second, userID)
+	if target.Url != "" {
+		if err := p.enqueue(target, event, payload, userID); err != nil {
+			errs = append(errs, err)
+		}
 	}
 
-	return nil
+	p.notifyWorker()
+
+	return errors.Join(errs...)
 }
 
-func (p *webhookProducer) sendWebhookWithRetry(url string, body []byte, maxRetries int, retryInterval time.Duration, userID string) {
-	for i := 0; i < maxRetries; i++ {
-		err, responseBody, statusCode := p.sendWebhook(url, body, userID)
-		if err == nil {
-			p.loggerWrapper.GetLogger(userID).LogInfo("[%s] webhook sent successfully - url: %s, status: %d, response: %s", userID, url, statusCode, string(responseBody))
-			return
+// ProduceToTargets grava no outbox uma entrega para cada destino adicional da instância
+func (p *webhookProducer) ProduceToTargets(
+	queueName string,
+	payload []byte,
+	targets []producer_interfaces.WebhookTarget,
+	userID string,
+) error {
+	event, ok := queueEvent(queueName)
+	if !ok {
+		return nil
+	}
+
+	var errs []error
+	for _, target := range targets {
+		if target.Url == "" {
+			continue
+		}
+		if err := p.enqueue(target, event, payload, userID); err != nil {
+			errs = append(errs, err)
 		}
-		p.loggerWrapper.GetLogger(userID).LogWarn("[%s] webhook failed - url: %s, attempt: %d, error: %v", userID, url, i+1, err)
+	}
+
+	p.notifyWorker()
+
+	return errors.Join(errs...)
+}
 
-		time.Sleep(retryInterval)
+// queueEvent extrai o nome do evento da fila no formato instanceId.evento
+func queueEvent(queueName string) (string, bool) {
+	splitQueue := strings.Split(queueName, ".")
+
+	if len(splitQueue) < 2 {
+		return "", false
 	}
-	p.loggerWrapper.GetLogger(userID).LogError("[%s] webhook failed after maximum retries - url: %s", userID, url)
+
+	return strings.Join(splitQueue[1:], "."), true
 }
 
-func (p *webhookProducer) sendWebhook(url string, body []byte, userID string) (error, []byte, int) {
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(body))
+func (p *webhookProducer) enqueue(target producer_interfaces.WebhookTarget, event string, payload []byte, userID string) error {
+	outbox := &webhook_model.WebhookOutbox{
+		InstanceId:    userID,
+		Event:         event,
+		Url:           target.Url,
+		Headers:       target.Headers,
+		Batch:         target.Batch,
+		Payload:       payload,
+		NextAttemptAt: time.Now(),
+	}
+
+	// Os segredos de assinatura não ficam em texto puro no banco; o worker os decifra a cada envio
+	err := p.sealSecrets(outbox, target)
+	if err == nil {
+		if target.Batch.Enabled() {
+			err = p.enqueueBatched(outbox, target, userID)
+		} else {
+			err = p.webhookRepository.EnqueueOutbox(outbox)
+		}
+	}
+	if err != nil {
+		// Sem o outbox a entrega ainda é tentada uma vez para não descartar o evento
+		p.loggerWrapper.GetLogger(userID).LogError("[%s] failed to persist webhook delivery, sending without retries - url: %s, error: %v", userID, target.Url, err)
+		go p.sendWebhook(target, payload, userID)
+		return err
+	}
+
+	return nil
+}
+
+func (p *webhookProducer) sealSecrets(outbox *webhook_model.WebhookOutbox, target producer_interfaces.WebhookTarget) error {
+	secret, err := secret_box.Seal(p.encryptionKey, target.Secret)
+	if err != nil {
+		return err
+	}
+
+	previousSecret, err := secret_box.Seal(p.encryptionKey, target.PreviousSecret)
+	if err != nil {
+		return err
+	}
+
+	outbox.Secret, outbox.PreviousSecret = secret, previousSecret
+
+	return nil
+}
+
+// outboxTarget monta o destino de uma entrega gravada, decifrando os segredos de assinatura
+func (p *webhookProducer) outboxTarget(outbox webhook_model.WebhookOutbox) (producer_interfaces.WebhookTarget, error) {
+	target := producer_interfaces.WebhookTarget{
+		Url:     outbox.Url,
+		Headers: outbox.Headers,
+		Batch:   outbox.Batch,
+	}
+
+	secret, err := secret_box.Open(p.encryptionKey, outbox.Secret)
+	if err != nil {
+		return target, err
+	}
+
+	previousSecret, err := secret_box.Open(p.encryptionKey, outbox.PreviousSecret)
+	if err != nil {
+		return target, err
+	}
+
+	target.Secret, target.PreviousSecret = secret, previousSecret
+
+	return target, nil
+}
+
+func (p *webhookProducer) sendWebhook(target producer_interfaces.WebhookTarget, body []byte, userID string) (error, []byte, int) {
+	// No modo binário do CloudEvents os atributos seguem nos headers ce-* e o corpo é apenas o data
+	var cloudEventHeaders map[string]string
+	if p.cloudEventsBinary {
+		if headers, data, ok := event_envelope.CloudEventBinary(body); ok {
+			cloudEventHeaders, body = headers, data
+		}
+	}
+
+	return p.post(target, body, cloudEventHeaders)
+}
+
+// post faz o POST assinado; extraHeaders são aplicados depois dos headers personalizados do destino
+func (p *webhookProducer) post(target producer_interfaces.WebhookTarget, body []byte, extraHeaders map[string]string) (error, []byte, int) {
+	req, err := http.NewRequest("POST", target.Url, bytes.NewBuffer(body))
 	if err != nil {
 		return err, nil, 0
 	}
 
+	// Headers personalizados são aplicados antes para que não sobrescrevam o Content-Type e a assinatura
+	for key, value := range target.Headers {
+		req.Header.Set(key, value)
+	}
+
 	req.Header.Set("Content-Type", "application/json")
+	for key, value := range extraHeaders {
+		req.Header.Set(key, value)
+	}
+
+	// A assinatura é recalculada a cada tentativa para que o timestamp fique dentro da tolerância do receptor
+	signatureHeaders := webhook_signature.Headers([]string{target.Secret, target.PreviousSecret}, time.Now().Unix(), body)
+	for key, value := range signatureHeaders {
+		req.Header.Set(key, value)
+	}
 
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	resp, err := p.httpClient.Do(req)
 	if err != nil {
 		return err, nil, 0
 	}
@@ -0,0 +1,152 @@
+package websocket_producer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	instance_repository "github.com/EvolutionAPI/evolution-go/pkg/instance/repository"
+	message_service "github.com/EvolutionAPI/evolution-go/pkg/message/service"
+	send_service "github.com/EvolutionAPI/evolution-go/pkg/sendMessage/service"
+	"github.com/EvolutionAPI/evolution-go/pkg/utils"
+)
+
+var ErrUnknownCommand = errors.New("unknown action")
+
+// CommandHandler executa os comandos recebidos pelo WebSocket em nome de uma instância
+type CommandHandler interface {
+	Handle(instanceId string, action string, data json.RawMessage) (interface{}, error)
+}
+
+type commandHandler struct {
+	sendService        send_service.SendService
+	messageService     message_service.MessageService
+	instanceRepository instance_repository.InstanceRepository
+}
+
+// Handle aplica as mesmas validações dos endpoints HTTP equivalentes (/send/text, /send/media,
+// /message/react, /message/markread e /message/presence). A instância vem completa do repositório,
+// como no middleware de autenticação, para que os webhooks dos envios saiam assinados
+func (h *commandHandler) Handle(instanceId string, action string, data json.RawMessage) (interface{}, error) {
+	instance, err := h.instanceRepository.GetInstanceByID(instanceId)
+	if err != nil {
+		return nil, fmt.Errorf("instance not found")
+	}
+
+	switch action {
+	case "send.text":
+		var payload send_service.TextStruct
+		if err := json.Unmarshal(data, &payload); err != nil {
+			return nil, err
+		}
+		if payload.Number, err = normalizeNumber(payload.Number, payload.FormatJid); err != nil {
+			return nil, err
+		}
+		if payload.Text == "" {
+			return nil, errors.New("message body is required")
+		}
+
+		return h.sendService.SendText(&payload, instance)
+
+	case "send.media":
+		var payload send_service.MediaStruct
+		if err := json.Unmarshal(data, &payload); err != nil {
+			return nil, err
+		}
+		if payload.Number, err = normalizeNumber(payload.Number, payload.FormatJid); err != nil {
+			return nil, err
+		}
+		if payload.Url == "" {
+			return nil, errors.New("URL is required")
+		}
+		if payload.Type == "" {
+			return nil, errors.New("media type is required")
+		}
+
+		return h.sendService.SendMediaUrl(&payload, instance)
+
+	case "message.react":
+		var payload message_service.ReactStruct
+		if err := json.Unmarshal(data, &payload); err != nil {
+			return nil, err
+		}
+		if payload.Number, err = normalizeNumber(payload.Number, nil); err != nil {
+			return nil, err
+		}
+		if payload.Reaction == "" {
+			return nil, errors.New("message reaction is required")
+		}
+
+		return h.messageService.React(&payload, instance)
+
+	case "message.markread":
+		var payload message_service.MarkReadStruct
+		if err := json.Unmarshal(data, &payload); err != nil {
+			return nil, err
+		}
+		if payload.Number, err = normalizeNumber(payload.Number, nil); err != nil {
+			return nil, err
+		}
+		if len(payload.Id) < 1 {
+			return nil, errors.New("id is required")
+		}
+
+		ts, err := h.messageService.MarkRead(&payload, instance)
+		if err != nil {
+			return nil, err
+		}
+
+		return map[string]interface{}{"timestamp": ts}, nil
+
+	case "message.presence":
+		var payload message_service.ChatPresenceStruct
+		if err := json.Unmarshal(data, &payload); err != nil {
+			return nil, err
+		}
+		if payload.Number, err = normalizeNumber(payload.Number, nil); err != nil {
+			return nil, err
+		}
+		if payload.State == "" {
+			return nil, errors.New("state is required")
+		}
+
+		ts, err := h.messageService.ChatPresence(&payload, instance)
+		if err != nil {
+			return nil, err
+		}
+
+		return map[string]interface{}{"timestamp": ts}, nil
+	}
+
+	return nil, ErrUnknownCommand
+}
+
+// normalizeNumber segue o middleware de validação de JID: formatJid=false mantém o número como recebido
+func normalizeNumber(number string, formatJid *bool) (string, error) {
+	if number == "" {
+		return "", errors.New("phone number is required")
+	}
+
+	if formatJid != nil && !*formatJid {
+		return number, nil
+	}
+
+	normalized, err := utils.CreateJID(number)
+	if err != nil {
+		return "", fmt.Errorf("Invalid number format: %s", err.Error())
+	}
+
+	return normalized, nil
+}
+
+func NewCommandHandler(
+	sendService send_service.SendService,
+	messageService message_service.MessageService,
+	instanceRepository instance_repository.InstanceRepository,
+) CommandHandler {
+	return &commandHandler{
+		sendService:        sendService,
+		messageService:     messageService,
+		instanceRepository: instanceRepository,
+	}
+}
@@ -1,15 +1,26 @@
 package websocket_producer
 
 import (
+	"encoding/json"
 	"net/http"
 	"strings"
 	"sync"
+	"time"
 
+	"github.com/EvolutionAPI/evolution-go/pkg/internal/event_types"
 	logger_wrapper "github.com/EvolutionAPI/evolution-go/pkg/logger"
 	"github.com/gomessguii/logger"
 	"github.com/gorilla/websocket"
 )
 
+const (
+	clientSendBuffer = 256
+	maxPendingCmds   = 10
+	writeWait        = 10 * time.Second
+	pongWait         = 60 * time.Second
+	pingPeriod       = (pongWait * 9) / 10
+)
+
 var upgrader = websocket.Upgrader{
 	ReadBufferSize:  1024,
 	WriteBufferSize: 1024,
@@ -19,17 +30,47 @@ var upgrader = websocket.Upgrader{
 	},
 }
 
+// client é uma conexão WebSocket com fila de escrita própria, para que um cliente lento não bloqueie os demais
+type client struct {
+	conn       *websocket.Conn
+	instanceId string // vazio recebe eventos de todas as instâncias
+	send       chan []byte
+	done       chan struct{}
+	commands   chan struct{} // limita os comandos em execução simultânea por conexão
+	closeOnce  sync.Once
+	eventsMux  sync.RWMutex
+	events     map[string]bool // vazio recebe todos os eventos
+}
+
+// clientMessage é a mensagem enviada pelo cliente, ex.: {"action":"subscribe","events":["MESSAGE","connected"]}
+// ou um comando, ex.: {"action":"send.text","requestId":"1","data":{"number":"...","text":"..."}}
+type clientMessage struct {
+	Action    string          `json:"action"`
+	RequestId string          `json:"requestId"`
+	Events    []string        `json:"events"`
+	Data      json.RawMessage `json:"data"`
+}
+
+// commandResponse é enviada na mesma conexão com o requestId do comando
+type commandResponse struct {
+	Type      string      `json:"type"`
+	RequestId string      `json:"requestId"`
+	Action    string      `json:"action"`
+	Status    string      `json:"status"`
+	Data      interface{} `json:"data,omitempty"`
+	Error     string      `json:"error,omitempty"`
+}
+
 type websocketProducer struct {
-	clients       map[string]*websocket.Conn // conexões específicas por instância
-	broadcast     []*websocket.Conn          // conexões que recebem todos os eventos
-	clientsMux    sync.RWMutex
-	loggerWrapper *logger_wrapper.LoggerManager
+	clients        map[string]map[*client]struct{} // conexões por instância; a chave vazia recebe todos os eventos
+	clientsMux     sync.RWMutex
+	commandHandler CommandHandler
+	loggerWrapper  *logger_wrapper.LoggerManager
 }
 
 func NewWebsocketProducer(loggerWrapper *logger_wrapper.LoggerManager) *websocketProducer {
 	return &websocketProducer{
-		clients:       make(map[string]*websocket.Conn),
-		broadcast:     make([]*websocket.Conn, 0),
+		clients:       make(map[string]map[*client]struct{}),
 		clientsMux:    sync.RWMutex{},
 		loggerWrapper: loggerWrapper,
 	}
@@ -46,89 +87,289 @@ func ServeWs(w http.ResponseWriter, r *http.Request, instanceId string, producer
 
 	logger.LogInfo("Conexão WebSocket estabelecida com sucesso")
 
-	if instanceId == "" {
-		producer.AddBroadcastClient(conn)
-	} else {
-		producer.AddClient(instanceId, conn)
+	c := &client{
+		conn:       conn,
+		instanceId: instanceId,
+		send:       make(chan []byte, clientSendBuffer),
+		done:       make(chan struct{}),
+		commands:   make(chan struct{}, maxPendingCmds),
 	}
 
-	// Goroutine para limpar conexão quando fechada
-	go func() {
-		for {
-			_, _, err := conn.ReadMessage()
-			if err != nil {
-				if instanceId == "" {
-					producer.RemoveBroadcastClient(conn)
-				} else {
-					producer.RemoveClient(instanceId)
-				}
-				conn.Close()
-				break
-			}
-		}
-	}()
+	producer.addClient(c)
+
+	go producer.writePump(c)
+	go producer.readPump(c)
 }
 
-func (p *websocketProducer) AddBroadcastClient(conn *websocket.Conn) {
+// SetCommandHandler habilita os comandos enviados pelas conexões de uma instância
+func (p *websocketProducer) SetCommandHandler(handler CommandHandler) {
 	p.clientsMux.Lock()
 	defer p.clientsMux.Unlock()
-	p.broadcast = append(p.broadcast, conn)
-	logger.LogInfo("Cliente broadcast websocket adicionado")
+	p.commandHandler = handler
 }
 
-func (p *websocketProducer) RemoveBroadcastClient(conn *websocket.Conn) {
+func (p *websocketProducer) addClient(c *client) {
 	p.clientsMux.Lock()
 	defer p.clientsMux.Unlock()
-	for i, c := range p.broadcast {
-		if c == conn {
-			p.broadcast = append(p.broadcast[:i], p.broadcast[i+1:]...)
-			break
-		}
+
+	if p.clients[c.instanceId] == nil {
+		p.clients[c.instanceId] = make(map[*client]struct{})
+	}
+	p.clients[c.instanceId][c] = struct{}{}
+
+	if c.instanceId == "" {
+		logger.LogInfo("Cliente broadcast websocket adicionado")
+	} else {
+		p.loggerWrapper.GetLogger(c.instanceId).LogInfo("Cliente websocket adicionado para instância: %s (%d conexões)", c.instanceId, len(p.clients[c.instanceId]))
 	}
-	logger.LogInfo("Cliente broadcast websocket removido")
 }
 
-func (p *websocketProducer) AddClient(instanceID string, conn *websocket.Conn) {
+func (p *websocketProducer) removeClient(c *client) {
 	p.clientsMux.Lock()
 	defer p.clientsMux.Unlock()
-	p.clients[instanceID] = conn
-	p.loggerWrapper.GetLogger(instanceID).LogInfo("Cliente websocket adicionado para instância: %s", instanceID)
+
+	if _, ok := p.clients[c.instanceId][c]; !ok {
+		return
+	}
+
+	delete(p.clients[c.instanceId], c)
+	if len(p.clients[c.instanceId]) == 0 {
+		delete(p.clients, c.instanceId)
+	}
+
+	if c.instanceId == "" {
+		logger.LogInfo("Cliente broadcast websocket removido")
+	} else {
+		p.loggerWrapper.GetLogger(c.instanceId).LogInfo("Cliente websocket removido para instância: %s", c.instanceId)
+	}
 }
 
-func (p *websocketProducer) RemoveClient(instanceID string) {
-	p.clientsMux.Lock()
-	defer p.clientsMux.Unlock()
-	delete(p.clients, instanceID)
-	p.loggerWrapper.GetLogger(instanceID).LogInfo("Cliente websocket removido para instância: %s", instanceID)
+func (c *client) close() {
+	c.closeOnce.Do(func() {
+		close(c.done)
+		c.conn.Close()
+	})
+}
+
+// readPump processa as mensagens do cliente e remove a conexão quando ela é fechada
+func (p *websocketProducer) readPump(c *client) {
+	defer func() {
+		p.removeClient(c)
+		c.close()
+	}()
+
+	c.conn.SetReadDeadline(time.Now().Add(pongWait))
+	c.conn.SetPongHandler(func(string) error {
+		return c.conn.SetReadDeadline(time.Now().Add(pongWait))
+	})
+
+	for {
+		_, data, err := c.conn.ReadMessage()
+		if err != nil {
+			return
+		}
+
+		var message clientMessage
+		if err := json.Unmarshal(data, &message); err != nil {
+			c.enqueue(mustMarshal(map[string]interface{}{"error": "invalid message"}))
+			continue
+		}
+
+		switch message.Action {
+		case "subscribe":
+			events := c.subscribe(message.Events)
+			c.enqueue(mustMarshal(map[string]interface{}{"action": "subscribed", "events": events}))
+		default:
+			p.runCommand(c, message)
+		}
+	}
+}
+
+// runCommand executa o comando fora do readPump, para que um envio demorado não bloqueie a leitura
+func (p *websocketProducer) runCommand(c *client, message clientMessage) {
+	response := commandResponse{
+		Type:      "response",
+		RequestId: message.RequestId,
+		Action:    message.Action,
+		Status:    "error",
+	}
+
+	p.clientsMux.RLock()
+	handler := p.commandHandler
+	p.clientsMux.RUnlock()
+
+	if handler == nil || message.Action == "" {
+		response.Error = ErrUnknownCommand.Error()
+		c.enqueue(mustMarshal(response))
+		return
+	}
+
+	if c.instanceId == "" {
+		response.Error = "commands require a connection bound to an instance"
+		c.enqueue(mustMarshal(response))
+		return
+	}
+
+	select {
+	case c.commands <- struct{}{}:
+	default:
+		response.Error = "too many pending commands"
+		c.enqueue(mustMarshal(response))
+		return
+	}
+
+	go func() {
+		defer func() { <-c.commands }()
+
+		data, err := handler.Handle(c.instanceId, message.Action, message.Data)
+		if err != nil {
+			p.loggerWrapper.GetLogger(c.instanceId).LogError("[%s] Erro ao executar comando websocket %s: %v", c.instanceId, message.Action, err)
+			response.Error = err.Error()
+		} else {
+			response.Status = "success"
+			response.Data = data
+		}
+
+		c.enqueue(mustMarshal(response))
+	}()
+}
+
+// writePump é o único escritor da conexão; envia a fila do cliente e os pings de keepalive
+func (p *websocketProducer) writePump(c *client) {
+	ticker := time.NewTicker(pingPeriod)
+	defer func() {
+		ticker.Stop()
+		c.close()
+	}()
+
+	for {
+		select {
+		case message := <-c.send:
+			c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+			if err := c.conn.WriteMessage(websocket.TextMessage, message); err != nil {
+				return
+			}
+		case <-ticker.C:
+			c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+			if err := c.conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				return
+			}
+		case <-c.done:
+			return
+		}
+	}
+}
+
+// enqueue coloca a mensagem na fila do cliente sem bloquear; se a fila estiver cheia o cliente é desconectado
+func (c *client) enqueue(message []byte) bool {
+	select {
+	case <-c.done:
+		return false
+	default:
+	}
+
+	select {
+	case c.send <- message:
+		return true
+	default:
+		c.close()
+		return false
+	}
+}
+
+// subscribe define os eventos do cliente. Aceita grupos (MESSAGE, CONNECTION) e eventos (message, connected);
+// lista vazia ou ALL voltam a receber todos os eventos
+func (c *client) subscribe(events []string) []string {
+	filter := make(map[string]bool)
+	accepted := make([]string, 0, len(events))
+
+	for _, event := range events {
+		event = strings.TrimSpace(event)
+		if event == "" {
+			continue
+		}
+
+		if strings.ToUpper(event) == event_types.ALL {
+			filter = make(map[string]bool)
+			accepted = []string{event_types.ALL}
+			break
+		}
+
+		if event_types.IsEventType(strings.ToUpper(event)) {
+			event = strings.ToUpper(event)
+		} else if event_types.GroupOf(event) != "" {
+			event = strings.ToLower(event)
+		} else {
+			continue
+		}
+
+		filter[event] = true
+		accepted = append(accepted, event)
+	}
+
+	c.eventsMux.Lock()
+	c.events = filter
+	c.eventsMux.Unlock()
+
+	return accepted
+}
+
+func (c *client) wants(event string) bool {
+	c.eventsMux.RLock()
+	defer c.eventsMux.RUnlock()
+
+	if len(c.events) == 0 {
+		return true
+	}
+
+	return c.events[event] || c.events[event_types.GroupOf(event)]
+}
+
+func mustMarshal(v interface{}) []byte {
+	data, _ := json.Marshal(v)
+	return data
 }
 
 func (p *websocketProducer) Produce(queueName string, payload []byte, instanceID string, _ string) error {
-	message := map[string]interface{}{
+	message, err := json.Marshal(map[string]interface{}{
 		"queue":   strings.ToLower(queueName),
 		"payload": string(payload),
+	})
+	if err != nil {
+		return err
+	}
+
+	event := strings.ToLower(queueName)
+	if _, after, found := strings.Cut(event, "."); found {
+		event = after
 	}
 
 	p.clientsMux.RLock()
 	defer p.clientsMux.RUnlock()
 
-	// Envia para cliente específico da instância
-	if client, exists := p.clients[instanceID]; exists {
-		err := client.WriteJSON(message)
-		if err != nil {
-			p.loggerWrapper.GetLogger(instanceID).LogError("Erro ao enviar mensagem websocket para %s: %v", instanceID, err)
-			// Não remove o cliente aqui pois estamos com o RLock
-			return err
+	// Envia para os clientes da instância e para os clientes broadcast; a escrita acontece no writePump de cada um
+	sent, dropped := 0, 0
+	for _, key := range []string{instanceID, ""} {
+		for c := range p.clients[key] {
+			if !c.wants(event) {
+				continue
+			}
+			if c.enqueue(message) {
+				sent++
+			} else {
+				dropped++
+			}
+		}
+		if instanceID == "" {
+			break
 		}
-		p.loggerWrapper.GetLogger(instanceID).LogInfo("Mensagem websocket enviada com sucesso para instância %s na fila %s", instanceID, queueName)
 	}
 
-	// Envia para todos os clientes broadcast
-	for _, conn := range p.broadcast {
-		err := conn.WriteJSON(message)
-		if err != nil {
-			p.loggerWrapper.GetLogger(instanceID).LogError("Erro ao enviar mensagem broadcast websocket: %v", err)
-			continue
-		}
+	if dropped > 0 {
+		p.loggerWrapper.GetLogger(instanceID).LogWarn("Fila de escrita websocket cheia, %d cliente(s) desconectado(s) na instância %s", dropped, instanceID)
+	}
+	if sent > 0 {
+		p.loggerWrapper.GetLogger(instanceID).LogInfo("Mensagem websocket enfileirada para %d cliente(s) da instância %s na fila %s", sent, instanceID, queueName)
 	}
 
 	return nil
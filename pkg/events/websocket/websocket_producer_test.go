@@ -0,0 +1,54 @@
+package websocket_producer
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestClientSubscribe(t *testing.T) {
+	tests := []struct {
+		name     string
+		events   []string
+		accepted []string
+		wants    map[string]bool
+	}{
+		{
+			name:     "No filter receives everything",
+			events:   nil,
+			accepted: []string{},
+			wants:    map[string]bool{"message": true, "connected": true},
+		},
+		{
+			name:     "Group filter",
+			events:   []string{"MESSAGE"},
+			accepted: []string{"MESSAGE"},
+			wants:    map[string]bool{"message": true, "connected": false, "receipt": false},
+		},
+		{
+			name:     "Specific events are case insensitive and unknown ones are dropped",
+			events:   []string{"Connected", "ReadReceipt", "qrcode"},
+			accepted: []string{"connected", "QRCODE"},
+			wants:    map[string]bool{"connected": true, "disconnected": false, "qrtimeout": true},
+		},
+		{
+			name:     "ALL clears the filter",
+			events:   []string{"MESSAGE", "all"},
+			accepted: []string{"ALL"},
+			wants:    map[string]bool{"message": true, "calloffer": true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &client{}
+			if got := c.subscribe(tt.events); !reflect.DeepEqual(got, tt.accepted) {
+				t.Errorf("subscribe() = %v, want %v", got, tt.accepted)
+			}
+			for event, expected := range tt.wants {
+				if got := c.wants(event); got != expected {
+					t.Errorf("wants(%q) = %v, want %v", event, got, expected)
+				}
+			}
+		})
+	}
+}
@@ -2,11 +2,14 @@ package instance_handler
 
 import (
 	"net/http"
+	"net/url"
+	"slices"
 	"time"
 
 	"github.com/gin-gonic/gin"
 
 	config "github.com/EvolutionAPI/evolution-go/pkg/config"
+	event_envelope "github.com/EvolutionAPI/evolution-go/pkg/events/envelope"
 	instance_model "github.com/EvolutionAPI/evolution-go/pkg/instance/model"
 	instance_service "github.com/EvolutionAPI/evolution-go/pkg/instance/service"
 )
@@ -29,6 +32,7 @@ type InstanceHandler interface {
 	GetLogs(ctx *gin.Context)
 	GetAdvancedSettings(ctx *gin.Context)
 	UpdateAdvancedSettings(ctx *gin.Context)
+	RotateWebhookSecret(ctx *gin.Context)
 }
 
 type instanceHandler struct {
@@ -132,6 +136,26 @@ func (i *instanceHandler) Connect(ctx *gin.Context) {
 		return
 	}
 
+	if !event_envelope.IsFormat(data.EventFormat) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid eventFormat, use raw, v1 or cloudevents"})
+		return
+	}
+
+	if err := data.WebhookBatch.Validate(); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if !validBrokerUrl(data.RabbitmqUrl, "amqp", "amqps") {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid rabbitmqUrl, use amqp:// or amqps://"})
+		return
+	}
+
+	if !validBrokerUrl(data.NatsUrl, "nats", "tls") {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid natsUrl, use nats:// or tls://"})
+		return
+	}
+
 	instance, jid, eventString, err := i.instanceService.Connect(data, instance)
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
@@ -639,6 +663,57 @@ func (h *instanceHandler) UpdateAdvancedSettings(c *gin.Context) {
 	})
 }
 
+// RotateWebhookSecret rotates the webhook signing secret of an instance
+// @Summary Rotate webhook secret
+// @Description Sets a new webhook signing secret (generated when not provided). The previous secret keeps signing deliveries during the overlap window.
+// @Tags Instance
+// @Accept json
+// @Produce json
+// @Param instanceId path string true "Instance ID"
+// @Param secret body instance_service.RotateWebhookSecretStruct false "Optional secret"
+// @Success 200 {object} gin.H "Webhook secret rotated successfully"
+// @Failure 400 {object} gin.H "Error on validation"
+// @Failure 500 {object} gin.H "Internal server error"
+// @Router /instance/webhook-secret/{instanceId} [post]
+func (i *instanceHandler) RotateWebhookSecret(ctx *gin.Context) {
+	instanceId := ctx.Param("instanceId")
+
+	if instanceId == "" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "instanceId is required"})
+		return
+	}
+
+	var data instance_service.RotateWebhookSecretStruct
+	if ctx.Request.ContentLength > 0 {
+		if err := ctx.ShouldBindJSON(&data); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	secret, err := i.instanceService.RotateWebhookSecret(instanceId, data.Secret)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "success", "data": secret})
+}
+
+// validBrokerUrl aceita URL vazia (broker padrão) ou uma URL com host e um dos esquemas informados
+func validBrokerUrl(brokerUrl string, schemes ...string) bool {
+	if brokerUrl == "" {
+		return true
+	}
+
+	parsed, err := url.Parse(brokerUrl)
+	if err != nil {
+		return false
+	}
+
+	return parsed.Host != "" && slices.Contains(schemes, parsed.Scheme)
+}
+
 func NewInstanceHandler(instanceService instance_service.InstanceService, config *config.Config) InstanceHandler {
 	return &instanceHandler{instanceService: instanceService, config: config}
 }
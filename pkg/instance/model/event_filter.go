@@ -0,0 +1,99 @@
+package instance_model
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// EventFilter define regras por instância aplicadas aos eventos antes de qualquer transporte.
+// Listas vazias e flags desligadas não filtram nada
+type EventFilter struct {
+	IncludeChats []string `json:"includeChats,omitempty"` // se preenchida, apenas estes chats geram eventos
+	ExcludeChats []string `json:"excludeChats,omitempty"`
+	IgnoreFromMe bool     `json:"ignoreFromMe,omitempty"` // descarta mensagens enviadas pela própria conta
+	MessageTypes []string `json:"messageTypes,omitempty"` // tipos de utils.GetMessageType, ex.: text, image, audio
+	OnlyMedia    bool     `json:"onlyMedia,omitempty"`
+}
+
+// AllowChat aplica as regras de chat; aceita JIDs completos ou apenas o número/ID do chat
+func (f *EventFilter) AllowChat(chat string) bool {
+	if f == nil {
+		return true
+	}
+
+	if len(f.IncludeChats) > 0 && !matchChat(f.IncludeChats, chat) {
+		return false
+	}
+
+	return !matchChat(f.ExcludeChats, chat)
+}
+
+// AllowMessage aplica todas as regras a uma mensagem recebida
+func (f *EventFilter) AllowMessage(chat string, fromMe bool, messageType string, isMedia bool) bool {
+	if f == nil {
+		return true
+	}
+
+	if !f.AllowChat(chat) {
+		return false
+	}
+
+	if f.IgnoreFromMe && fromMe {
+		return false
+	}
+
+	if f.OnlyMedia && !isMedia {
+		return false
+	}
+
+	if len(f.MessageTypes) > 0 {
+		for _, allowed := range f.MessageTypes {
+			// utils.GetMessageType inclui o mimetype na mídia ("image image/jpeg"), então "image" também aceita
+			allowed = strings.ToLower(strings.TrimSpace(allowed))
+			if messageType == allowed || strings.HasPrefix(messageType, allowed+" ") {
+				return true
+			}
+		}
+		return false
+	}
+
+	return true
+}
+
+func matchChat(chats []string, chat string) bool {
+	user, _, _ := strings.Cut(chat, "@")
+
+	for _, candidate := range chats {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == chat || (!strings.Contains(candidate, "@") && candidate == user) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Value grava o filtro como JSON
+func (f EventFilter) Value() (driver.Value, error) {
+	data, err := json.Marshal(f)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+// Scan lê o filtro gravado como JSON
+func (f *EventFilter) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*f = EventFilter{}
+		return nil
+	case string:
+		return json.Unmarshal([]byte(v), f)
+	case []byte:
+		return json.Unmarshal(v, f)
+	}
+	return fmt.Errorf("unsupported event filter value: %T", value)
+}
@@ -0,0 +1,49 @@
+package instance_model
+
+import "testing"
+
+func TestEventFilterAllowMessage(t *testing.T) {
+	tests := []struct {
+		name        string
+		filter      *EventFilter
+		chat        string
+		fromMe      bool
+		messageType string
+		isMedia     bool
+		want        bool
+	}{
+		{"nil filter", nil, "5511@s.whatsapp.net", true, "text", false, true},
+		{"included chat by number", &EventFilter{IncludeChats: []string{"5511"}}, "5511@s.whatsapp.net", false, "text", false, true},
+		{"chat not included", &EventFilter{IncludeChats: []string{"5511@s.whatsapp.net"}}, "5522@s.whatsapp.net", false, "text", false, false},
+		{"excluded group", &EventFilter{ExcludeChats: []string{"1203@g.us"}}, "1203@g.us", false, "text", false, false},
+		{"number does not match other server", &EventFilter{ExcludeChats: []string{"1203@s.whatsapp.net"}}, "1203@g.us", false, "text", false, true},
+		{"from me ignored", &EventFilter{IgnoreFromMe: true}, "5511@s.whatsapp.net", true, "text", false, false},
+		{"media type prefix", &EventFilter{MessageTypes: []string{"image", "audio"}}, "5511@s.whatsapp.net", false, "image image/jpeg", true, true},
+		{"type not allowed", &EventFilter{MessageTypes: []string{"image"}}, "5511@s.whatsapp.net", false, "text", false, false},
+		{"only media", &EventFilter{OnlyMedia: true}, "5511@s.whatsapp.net", false, "text", false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.filter.AllowMessage(tt.chat, tt.fromMe, tt.messageType, tt.isMedia); got != tt.want {
+				t.Errorf("AllowMessage() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEventFilterScan(t *testing.T) {
+	var filter EventFilter
+	if err := filter.Scan(`{"excludeChats":["5511"],"onlyMedia":true}`); err != nil {
+		t.Fatalf("Scan() error = %v", err)
+	}
+
+	if len(filter.ExcludeChats) != 1 || !filter.OnlyMedia {
+		t.Errorf("Scan() = %+v", filter)
+	}
+
+	value, err := filter.Value()
+	if err != nil || value != `{"excludeChats":["5511"],"onlyMedia":true}` {
+		t.Errorf("Value() = %v, %v", value, err)
+	}
+}
@@ -3,6 +3,8 @@ package instance_model
 import (
 	"time"
 
+	producer_interfaces "github.com/EvolutionAPI/evolution-go/pkg/events/interfaces"
+	webhook_model "github.com/EvolutionAPI/evolution-go/pkg/webhook/model"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
@@ -12,9 +14,14 @@ type Instance struct {
 	Name             string    `json:"name"`
 	Token            string    `json:"token" gorm:"unique"`
 	Webhook          string    `json:"webhook"`
+	WebhookSecret    string    `json:"webhookSecret"`
 	RabbitmqEnable   string    `json:"rabbitmqEnable"`
 	WebSocketEnable  string    `json:"websocketEnable"`
 	NatsEnable       string    `json:"natsEnable"`
+	KafkaEnable      string    `json:"kafkaEnable"`
+	RedisEnable      string    `json:"redisEnable"`
+	MqttEnable       string    `json:"mqttEnable"`
+	EventFormat      string    `json:"eventFormat"`
 	Jid              string    `json:"jid" gorm:"column:jid"`
 	Qrcode           string    `json:"qrcode" gorm:"type:text"`
 	Connected        bool      `json:"connected"`
@@ -26,6 +33,21 @@ type Instance struct {
 	ClientName       string    `json:"client_name"`
 	CreatedAt        time.Time `json:"createdAt" gorm:"autoCreateTime"`
 
+	// Segredo anterior continua assinando as entregas até expirar, permitindo rotação sem perda de eventos.
+	// Os dois segredos ficam cifrados com ENCRYPTION_KEY, como as cópias do outbox
+	WebhookSecretPrevious          string     `json:"-"`
+	WebhookSecretPreviousExpiresAt *time.Time `json:"-"`
+
+	// Agrupamento opcional das entregas do webhook da instância
+	WebhookBatch *webhook_model.WebhookBatch `json:"webhookBatch" gorm:"serializer:json"`
+
+	// Brokers próprios da instância; as URLs, com credenciais, ficam cifradas com ENCRYPTION_KEY
+	RabbitmqUrl string `json:"-" gorm:"type:text"`
+	NatsUrl     string `json:"-" gorm:"type:text"`
+
+	// Estado das conexões com os brokers, preenchido apenas no endpoint de info
+	Brokers map[string]producer_interfaces.BrokerHealth `json:"brokers,omitempty" gorm:"-"`
+
 	// Advanced Settings
 	AlwaysOnline  bool   `json:"alwaysOnline" gorm:"default:false"`
 	RejectCall    bool   `json:"rejectCall" gorm:"default:false"`
@@ -33,6 +55,9 @@ type Instance struct {
 	ReadMessages  bool   `json:"readMessages" gorm:"default:false"`
 	IgnoreGroups  bool   `json:"ignoreGroups" gorm:"default:false"`
 	IgnoreStatus  bool   `json:"ignoreStatus" gorm:"default:false"`
+
+	// Regras de filtro avaliadas antes de qualquer transporte
+	EventFilter *EventFilter `json:"eventFilter" gorm:"type:text"`
 }
 
 // AdvancedSettings representa as configurações avançadas de uma instância
@@ -43,6 +68,8 @@ type AdvancedSettings struct {
 	ReadMessages  bool   `json:"readMessages"`
 	IgnoreGroups  bool   `json:"ignoreGroups"`
 	IgnoreStatus  bool   `json:"ignoreStatus"`
+
+	EventFilter *EventFilter `json:"eventFilter"`
 }
 
 func (m *Instance) BeforeCreate(tx *gorm.DB) (err error) {
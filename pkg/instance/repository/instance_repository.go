@@ -8,11 +8,15 @@ import (
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 
+	chat_model "github.com/EvolutionAPI/evolution-go/pkg/chat/model"
+
 	label_model "github.com/EvolutionAPI/evolution-go/pkg/label/model"
 	label_repository "github.com/EvolutionAPI/evolution-go/pkg/label/repository"
 
 	message_model "github.com/EvolutionAPI/evolution-go/pkg/message/model"
 	message_repository "github.com/EvolutionAPI/evolution-go/pkg/message/repository"
+
+	webhook_model "github.com/EvolutionAPI/evolution-go/pkg/webhook/model"
 )
 
 type InstanceRepository interface {
@@ -154,10 +158,31 @@ func (i *instanceRepository) Delete(instanceId string) error {
 		}
 
 		// Deleta todas as mensagens associadas à instância
-		if err := tx.Where("source = ?", instanceId).Delete(&message_model.Message{}).Error; err != nil {
+		if err := tx.Where("instance_id = ?", instanceId).Delete(&message_model.Message{}).Error; err != nil {
 			return fmt.Errorf("erro ao deletar mensagens: %v", err)
 		}
 
+		if err := tx.Where("instance_id = ?", instanceId).Delete(&chat_model.Chat{}).Error; err != nil {
+			return fmt.Errorf("erro ao deletar chats: %v", err)
+		}
+
+		// Deleta os webhooks adicionais, as entregas pendentes, em dead letter e o log de tentativas da instância
+		if err := tx.Where("instance_id = ?", instanceId).Delete(&webhook_model.WebhookOutbox{}).Error; err != nil {
+			return fmt.Errorf("erro ao deletar outbox de webhook: %v", err)
+		}
+
+		if err := tx.Where("instance_id = ?", instanceId).Delete(&webhook_model.WebhookDeadLetter{}).Error; err != nil {
+			return fmt.Errorf("erro ao deletar dead letters de webhook: %v", err)
+		}
+
+		if err := tx.Where("instance_id = ?", instanceId).Delete(&webhook_model.WebhookDelivery{}).Error; err != nil {
+			return fmt.Errorf("erro ao deletar log de entregas de webhook: %v", err)
+		}
+
+		if err := tx.Where("instance_id = ?", instanceId).Delete(&webhook_model.InstanceWebhook{}).Error; err != nil {
+			return fmt.Errorf("erro ao deletar webhooks adicionais: %v", err)
+		}
+
 		// Deleta a instância
 		if err := tx.Where("id = ?", instanceId).Delete(&instance_model.Instance{}).Error; err != nil {
 			return fmt.Errorf("erro ao deletar instância: %v", err)
@@ -174,7 +199,7 @@ func (i *instanceRepository) GetAdvancedSettings(instanceId string) (*instance_m
 	}
 
 	var instance instance_model.Instance
-	err := i.db.Select("always_online, reject_call, msg_reject_call, read_messages, ignore_groups, ignore_status").
+	err := i.db.Select("always_online, reject_call, msg_reject_call, read_messages, ignore_groups, ignore_status, event_filter").
 		Where("id = ?", instanceId).First(&instance).Error
 	if err != nil {
 		return nil, err
@@ -187,6 +212,7 @@ func (i *instanceRepository) GetAdvancedSettings(instanceId string) (*instance_m
 		ReadMessages:  instance.ReadMessages,
 		IgnoreGroups:  instance.IgnoreGroups,
 		IgnoreStatus:  instance.IgnoreStatus,
+		EventFilter:   instance.EventFilter,
 	}
 
 	return settings, nil
@@ -205,6 +231,7 @@ func (i *instanceRepository) UpdateAdvancedSettings(instanceId string, settings
 		"read_messages":   settings.ReadMessages,
 		"ignore_groups":   settings.IgnoreGroups,
 		"ignore_status":   settings.IgnoreStatus,
+		"event_filter":    settings.EventFilter,
 	}
 
 	err := i.db.Model(&instance_model.Instance{}).Where("id = ?", instanceId).Updates(updates).Error
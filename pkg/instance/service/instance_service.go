@@ -14,10 +14,13 @@ import (
 	"time"
 
 	"github.com/EvolutionAPI/evolution-go/pkg/config"
+	webhook_signature "github.com/EvolutionAPI/evolution-go/pkg/events/webhook/signature"
 	instance_model "github.com/EvolutionAPI/evolution-go/pkg/instance/model"
 	instance_repository "github.com/EvolutionAPI/evolution-go/pkg/instance/repository"
 	event_types "github.com/EvolutionAPI/evolution-go/pkg/internal/event_types"
+	"github.com/EvolutionAPI/evolution-go/pkg/internal/secret_box"
 	logger_wrapper "github.com/EvolutionAPI/evolution-go/pkg/logger"
+	webhook_model "github.com/EvolutionAPI/evolution-go/pkg/webhook/model"
 	whatsmeow_service "github.com/EvolutionAPI/evolution-go/pkg/whatsmeow/service"
 	"go.mau.fi/whatsmeow"
 	"go.mau.fi/whatsmeow/types"
@@ -43,6 +46,7 @@ type InstanceService interface {
 	GetLogs(instanceId string, startDate, endDate time.Time, level string, limit int) ([]logger_wrapper.LogEntry, error)
 	GetAdvancedSettings(instanceId string) (*instance_model.AdvancedSettings, error)
 	UpdateAdvancedSettings(instanceId string, settings *instance_model.AdvancedSettings) error
+	RotateWebhookSecret(instanceId string, secret string) (*WebhookSecretStruct, error)
 }
 
 type instances struct {
@@ -71,12 +75,22 @@ type CreateStruct struct {
 
 type ConnectStruct struct {
 	WebhookUrl      string   `json:"webhookUrl"`
+	WebhookSecret   string   `json:"webhookSecret"`
 	Subscribe       []string `json:"subscribe"`
 	Immediate       bool     `json:"immediate"`
 	Phone           string   `json:"phone"`
 	RabbitmqEnable  string   `json:"rabbitmqEnable"`
 	WebSocketEnable string   `json:"websocketEnable"`
 	NatsEnable      string   `json:"natsEnable"`
+	RabbitmqUrl     string   `json:"rabbitmqUrl"`
+	NatsUrl         string   `json:"natsUrl"`
+	KafkaEnable     string   `json:"kafkaEnable"`
+	RedisEnable     string   `json:"redisEnable"`
+	MqttEnable      string   `json:"mqttEnable"`
+	EventFormat     string   `json:"eventFormat"` // raw, v1 ou cloudevents; vazio usa EVENT_FORMAT
+
+	// Agrupa as entregas do webhookUrl em POSTs com arrays de eventos
+	WebhookBatch *webhook_model.WebhookBatch `json:"webhookBatch"`
 }
 
 type StatusStruct struct {
@@ -111,6 +125,15 @@ type ForceReconnectStruct struct {
 	Number string `json:"number"`
 }
 
+type RotateWebhookSecretStruct struct {
+	Secret string `json:"secret"`
+}
+
+type WebhookSecretStruct struct {
+	Secret                  string     `json:"secret"`
+	PreviousSecretExpiresAt *time.Time `json:"previousSecretExpiresAt,omitempty"`
+}
+
 func (i *instances) ensureClientConnected(instanceId string) (*whatsmeow.Client, error) {
 	logger := i.loggerWrapper.GetLogger(instanceId)
 	client := i.clientPointer[instanceId]
@@ -181,6 +204,7 @@ func (i instances) Create(data *CreateStruct) (*instance_model.Instance, error)
 		instance.ReadMessages = data.AdvancedSettings.ReadMessages
 		instance.IgnoreGroups = data.AdvancedSettings.IgnoreGroups
 		instance.IgnoreStatus = data.AdvancedSettings.IgnoreStatus
+		instance.EventFilter = data.AdvancedSettings.EventFilter
 	}
 
 	createdInstance, err := i.instanceRepository.Create(instance)
@@ -216,11 +240,40 @@ func (i instances) Connect(data *ConnectStruct, instance *instance_model.Instanc
 
 	instance.Events = eventString
 	instance.Webhook = data.WebhookUrl
+	instance.WebhookBatch = data.WebhookBatch
+	if data.WebhookSecret != "" {
+		currentSecret, err := secret_box.Open(i.config.EncryptionKey, instance.WebhookSecret)
+		if err != nil {
+			return nil, "", "", err
+		}
+		if data.WebhookSecret != currentSecret {
+			if _, err := i.setWebhookSecret(instance, data.WebhookSecret); err != nil {
+				return nil, "", "", err
+			}
+		}
+	}
 	instance.RabbitmqEnable = data.RabbitmqEnable
 	instance.NatsEnable = data.NatsEnable
+
+	// Sem URL a instância volta a publicar nos brokers de AMQP_URL e NATS_URL
+	rabbitmqUrl, err := secret_box.Seal(i.config.EncryptionKey, data.RabbitmqUrl)
+	if err != nil {
+		return nil, "", "", err
+	}
+	natsUrl, err := secret_box.Seal(i.config.EncryptionKey, data.NatsUrl)
+	if err != nil {
+		return nil, "", "", err
+	}
+	instance.RabbitmqUrl = rabbitmqUrl
+	instance.NatsUrl = natsUrl
+
+	instance.KafkaEnable = data.KafkaEnable
+	instance.RedisEnable = data.RedisEnable
+	instance.MqttEnable = data.MqttEnable
+	instance.EventFormat = data.EventFormat
 	instance.WebSocketEnable = data.WebSocketEnable
 
-	err := i.instanceRepository.Update(instance)
+	err = i.instanceRepository.Update(instance)
 	if err != nil {
 		i.loggerWrapper.GetLogger(instance.Id).LogError("[%s] Error updating instance: %s", instance.Id, err)
 		return nil, "", "", err
@@ -450,6 +503,7 @@ func (i instances) GetAll() ([]*instance_model.Instance, error) {
 		}
 
 		instance.Proxy = ""
+		instance.WebhookSecret = ""
 	}
 
 	return instances, nil
@@ -469,6 +523,8 @@ func (i instances) Info(instanceId string) (*instance_model.Instance, error) {
 	}
 
 	instance.Proxy = ""
+	instance.WebhookSecret = ""
+	instance.Brokers = i.whatsmeowService.BrokerHealth(instance)
 
 	return instance, nil
 }
@@ -799,6 +855,70 @@ func (i instances) UpdateAdvancedSettings(instanceId string, settings *instance_
 	return nil
 }
 
+// setWebhookSecret troca o segredo de assinatura mantendo o anterior válido durante a janela de sobreposição.
+// O novo segredo é gravado cifrado, e o atual passa para o anterior como já está
+func (i instances) setWebhookSecret(instance *instance_model.Instance, secret string) (*time.Time, error) {
+	sealedSecret, err := secret_box.Seal(i.config.EncryptionKey, secret)
+	if err != nil {
+		return nil, err
+	}
+
+	var expiresAt *time.Time
+
+	if instance.WebhookSecret != "" && i.config.WebhookSecretOverlap > 0 {
+		expiration := time.Now().Add(i.config.WebhookSecretOverlap)
+		expiresAt = &expiration
+		instance.WebhookSecretPrevious = instance.WebhookSecret
+	} else {
+		instance.WebhookSecretPrevious = ""
+	}
+
+	instance.WebhookSecret = sealedSecret
+	instance.WebhookSecretPreviousExpiresAt = expiresAt
+
+	return expiresAt, nil
+}
+
+func (i instances) RotateWebhookSecret(instanceId string, secret string) (*WebhookSecretStruct, error) {
+	instance, err := i.instanceRepository.GetInstanceByID(instanceId)
+	if err != nil {
+		return nil, err
+	}
+
+	if secret == "" {
+		secret, err = webhook_signature.GenerateSecret()
+		if err != nil {
+			i.loggerWrapper.GetLogger(instanceId).LogError("[%s] Failed to generate webhook secret: %v", instanceId, err)
+			return nil, err
+		}
+	}
+
+	expiresAt, err := i.setWebhookSecret(instance, secret)
+	if err != nil {
+		i.loggerWrapper.GetLogger(instanceId).LogError("[%s] Failed to encrypt webhook secret: %v", instanceId, err)
+		return nil, err
+	}
+
+	err = i.instanceRepository.Update(instance)
+	if err != nil {
+		i.loggerWrapper.GetLogger(instanceId).LogError("[%s] Failed to update webhook secret: %v", instanceId, err)
+		return nil, err
+	}
+
+	// Sincroniza o novo segredo na instância em execução
+	err = i.whatsmeowService.UpdateInstanceSettings(instanceId)
+	if err != nil {
+		i.loggerWrapper.GetLogger(instanceId).LogInfo("[%s] Instance not in runtime, webhook secret will be used when connected", instanceId)
+	}
+
+	i.loggerWrapper.GetLogger(instanceId).LogInfo("[%s] Webhook secret rotated", instanceId)
+
+	return &WebhookSecretStruct{
+		Secret:                  secret,
+		PreviousSecretExpiresAt: expiresAt,
+	}, nil
+}
+
 func NewInstanceService(
 	instanceRepository instance_repository.InstanceRepository,
 	killChannel map[string](chan bool),
@@ -0,0 +1,50 @@
+package instance_service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/EvolutionAPI/evolution-go/pkg/config"
+	instance_model "github.com/EvolutionAPI/evolution-go/pkg/instance/model"
+	"github.com/EvolutionAPI/evolution-go/pkg/internal/secret_box"
+)
+
+const encryptionKey = "test-encryption-key"
+
+func TestSetWebhookSecret(t *testing.T) {
+	service := instances{config: &config.Config{EncryptionKey: encryptionKey, WebhookSecretOverlap: time.Hour}}
+	instance := &instance_model.Instance{}
+
+	if _, err := service.setWebhookSecret(instance, "first"); err != nil {
+		t.Fatalf("setWebhookSecret() error = %v", err)
+	}
+	if instance.WebhookSecret == "first" {
+		t.Fatalf("Expected the webhook secret to be encrypted")
+	}
+
+	expiresAt, err := service.setWebhookSecret(instance, "second")
+	if err != nil {
+		t.Fatalf("setWebhookSecret() error = %v", err)
+	}
+	if expiresAt == nil {
+		t.Fatalf("Expected the previous secret to have an expiration")
+	}
+
+	tests := []struct {
+		name   string
+		sealed string
+		want   string
+	}{
+		{name: "Current secret", sealed: instance.WebhookSecret, want: "second"},
+		{name: "Previous secret", sealed: instance.WebhookSecretPrevious, want: "first"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := secret_box.Open(encryptionKey, tt.sealed)
+			if err != nil || got != tt.want {
+				t.Errorf("Expected %q, but got %q (%v)", tt.want, got, err)
+			}
+		})
+	}
+}
@@ -1,5 +1,7 @@
 package event_types
 
+import "strings"
+
 const (
 	ALL           = "ALL"
 	MESSAGE       = "MESSAGE"
@@ -15,6 +17,9 @@ const (
 	GROUP         = "GROUP"
 	NEWSLETTER    = "NEWSLETTER"
 	QRCODE        = "QRCODE"
+	PROFILE       = "PROFILE"
+	BLOCKLIST     = "BLOCKLIST"
+	CHAT_STATE    = "CHAT_STATE"
 )
 
 var AllEventTypes = []string{
@@ -31,6 +36,9 @@ var AllEventTypes = []string{
 	GROUP,
 	NEWSLETTER,
 	QRCODE,
+	PROFILE,
+	BLOCKLIST,
+	CHAT_STATE,
 }
 
 var validEventTypes = map[string]bool{
@@ -48,8 +56,107 @@ var validEventTypes = map[string]bool{
 	GROUP:         true,
 	NEWSLETTER:    true,
 	QRCODE:        true,
+	PROFILE:       true,
+	BLOCKLIST:     true,
+	CHAT_STATE:    true,
 }
 
 func IsEventType(eventType string) bool {
 	return validEventTypes[eventType]
 }
+
+// Event descreve um evento emitido pela API: o nome enviado no campo event do payload, o grupo usado
+// no subscribe e em *_GLOBAL_EVENTS, o sufixo das filas, tópicos e subjects e o tipo serializado em data
+type Event struct {
+	Name    string `json:"name"`
+	Group   string `json:"group"`
+	Queue   string `json:"queue"`
+	Payload string `json:"payload"`
+}
+
+// registry é a fonte única do mapeamento de eventos, usada por webhooks, filas globais e WebSocket
+var registry = []Event{
+	{"Message", MESSAGE, "message", "events.Message"},
+	{"SendMessage", SEND_MESSAGE, "sendmessage", "send_service.MessageSendStruct"},
+	{"Receipt", READ_RECEIPT, "receipt", "events.Receipt"},
+	{"Presence", PRESENCE, "presence", "events.Presence"},
+	{"HistorySync", HISTORY_SYNC, "historysync", "events.HistorySync"},
+	{"OfflineSyncCompleted", HISTORY_SYNC, "offlinesynccompleted", "events.OfflineSyncCompleted"},
+	{"ChatPresence", CHAT_PRESENCE, "chatpresence", "events.ChatPresence"},
+	{"Archive", CHAT_PRESENCE, "archive", "events.Archive"},
+	{"CallOffer", CALL, "calloffer", "events.CallOffer"},
+	{"CallAccept", CALL, "callaccept", "events.CallAccept"},
+	{"CallTerminate", CALL, "callterminate", "events.CallTerminate"},
+	{"CallOfferNotice", CALL, "calloffernotice", "events.CallOfferNotice"},
+	{"CallRelayLatency", CALL, "callrelaylatency", "events.CallRelayLatency"},
+	{"Connected", CONNECTION, "connected", "events.Connected"},
+	{"PairSuccess", CONNECTION, "pairsuccess", "events.PairSuccess"},
+	{"TemporaryBan", CONNECTION, "temporaryban", "events.TemporaryBan"},
+	{"LoggedOut", CONNECTION, "loggedout", "events.LoggedOut"},
+	{"ConnectFailure", CONNECTION, "connectfailure", "events.ConnectFailure"},
+	{"Disconnected", CONNECTION, "disconnected", "events.Disconnected"},
+	{"LabelEdit", LABEL, "labeledit", "events.LabelEdit"},
+	{"LabelAssociationChat", LABEL, "labelassociationchat", "events.LabelAssociationChat"},
+	{"LabelAssociationMessage", LABEL, "labelassociationmessage", "events.LabelAssociationMessage"},
+	{"Contact", CONTACT, "contact", "events.Contact"},
+	{"PushName", CONTACT, "pushname", "events.PushName"},
+	{"GroupInfo", GROUP, "groupinfo", "events.GroupInfo"},
+	{"JoinedGroup", GROUP, "joinedgroup", "events.JoinedGroup"},
+	{"NewsletterJoin", NEWSLETTER, "newsletterjoin", "events.NewsletterJoin"},
+	{"NewsletterLeave", NEWSLETTER, "newsletterleave", "events.NewsletterLeave"},
+	{"QRCode", QRCODE, "qrcode", "map[string]interface{}"},
+	{"QRTimeout", QRCODE, "qrtimeout", "map[string]interface{}"},
+	{"Picture", PROFILE, "picture", "events.Picture"},
+	{"UserAbout", PROFILE, "userabout", "events.UserAbout"},
+	{"IdentityChange", PROFILE, "identitychange", "events.IdentityChange"},
+	{"Blocklist", BLOCKLIST, "blocklist", "events.Blocklist"},
+	{"Mute", CHAT_STATE, "mute", "events.Mute"},
+	{"Pin", CHAT_STATE, "pin", "events.Pin"},
+	{"Star", CHAT_STATE, "star", "events.Star"},
+	{"DeleteForMe", CHAT_STATE, "deleteforme", "events.DeleteForMe"},
+	{"MarkChatAsRead", CHAT_STATE, "markchatasread", "events.MarkChatAsRead"},
+}
+
+// eventsByName indexa o registry pelo nome em minúsculas
+var eventsByName = func() map[string]Event {
+	index := make(map[string]Event, len(registry))
+	for _, event := range registry {
+		index[strings.ToLower(event.Name)] = event
+	}
+	return index
+}()
+
+// Events retorna uma cópia do registry, na ordem dos grupos
+func Events() []Event {
+	return append([]Event(nil), registry...)
+}
+
+// Lookup busca o evento pelo nome, sem diferenciar maiúsculas (ex.: "Message" ou "message")
+func Lookup(eventType string) (Event, bool) {
+	event, ok := eventsByName[strings.ToLower(eventType)]
+	return event, ok
+}
+
+// GroupOf retorna o grupo do evento (ex.: "Message" ou "message" -> MESSAGE), ou vazio se não mapeado
+func GroupOf(eventType string) string {
+	return eventsByName[strings.ToLower(eventType)].Group
+}
+
+// QueueOf retorna o sufixo de fila do evento; eventos fora do registry usam o nome em minúsculas
+func QueueOf(eventType string) string {
+	if event, ok := Lookup(eventType); ok {
+		return event.Queue
+	}
+	return strings.ToLower(eventType)
+}
+
+// EventsOf retorna os eventos de um grupo
+func EventsOf(group string) []Event {
+	var events []Event
+	for _, event := range registry {
+		if event.Group == group {
+			events = append(events, event)
+		}
+	}
+	return events
+}
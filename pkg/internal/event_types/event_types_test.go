@@ -0,0 +1,53 @@
+package event_types
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRegistry(t *testing.T) {
+	seen := make(map[string]bool)
+	for _, event := range Events() {
+		if seen[event.Name] {
+			t.Errorf("event %s registered twice", event.Name)
+		}
+		seen[event.Name] = true
+
+		if !IsEventType(event.Group) || event.Group == ALL {
+			t.Errorf("event %s has invalid group %q", event.Name, event.Group)
+		}
+		if event.Queue != strings.ToLower(event.Name) {
+			t.Errorf("event %s has queue %q, want %q", event.Name, event.Queue, strings.ToLower(event.Name))
+		}
+	}
+
+	for _, group := range AllEventTypes {
+		if len(EventsOf(group)) == 0 {
+			t.Errorf("group %s has no events", group)
+		}
+	}
+}
+
+func TestLookup(t *testing.T) {
+	tests := []struct {
+		event string
+		group string
+		queue string
+	}{
+		{"Message", MESSAGE, "message"},
+		{"callrelaylatency", CALL, "callrelaylatency"},
+		{"OfflineSyncCompleted", HISTORY_SYNC, "offlinesynccompleted"},
+		{"Unknown", "", "unknown"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.event, func(t *testing.T) {
+			if got := GroupOf(tt.event); got != tt.group {
+				t.Errorf("GroupOf(%q) = %q, want %q", tt.event, got, tt.group)
+			}
+			if got := QueueOf(tt.event); got != tt.queue {
+				t.Errorf("QueueOf(%q) = %q, want %q", tt.event, got, tt.queue)
+			}
+		})
+	}
+}
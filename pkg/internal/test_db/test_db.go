@@ -0,0 +1,88 @@
+package test_db
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+	"gorm.io/gorm/migrator"
+	"gorm.io/gorm/schema"
+	"modernc.org/sqlite"
+)
+
+var registerFunctions sync.Once
+
+// Open abre um SQLite em memória com o dialeto do Postgres, para que os testes de repositório executem
+// o mesmo SQL gerado em produção (ON CONFLICT, excluded, RETURNING) sem depender de um servidor.
+// As tabelas dos models informados são criadas antes do teste
+func Open(t *testing.T, models ...interface{}) *gorm.DB {
+	t.Helper()
+
+	registerFunctions.Do(func() {
+		// O SQLite não tem GREATEST; a versão inteira basta para os contadores usados nos repositórios
+		sqlite.MustRegisterDeterministicScalarFunction("greatest", -1, greatest)
+	})
+
+	sqlDB, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatalf("failed to open test database: %v", err)
+	}
+	// Cada conexão teria o próprio banco em memória
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { sqlDB.Close() })
+
+	db, err := gorm.Open(dialector{postgres.New(postgres.Config{Conn: sqlDB})}, &gorm.Config{
+		DisableAutomaticPing: true,
+		Logger:               logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatalf("failed to open test database: %v", err)
+	}
+
+	for _, model := range models {
+		if err := db.Migrator().CreateTable(model); err != nil {
+			t.Fatalf("failed to create table for %T: %v", model, err)
+		}
+	}
+
+	return db
+}
+
+// dialector é o dialeto do Postgres criando as colunas timestamptz como timestamp, o tipo que o driver
+// do SQLite devolve como time.Time
+type dialector struct {
+	gorm.Dialector
+}
+
+func (d dialector) DataTypeOf(field *schema.Field) string {
+	return strings.Replace(d.Dialector.DataTypeOf(field), "timestamptz", "timestamp", 1)
+}
+
+func (d dialector) Migrator(db *gorm.DB) gorm.Migrator {
+	return postgres.Migrator{Migrator: migrator.Migrator{Config: migrator.Config{
+		DB:                          db,
+		Dialector:                   d,
+		CreateIndexAfterCreateTable: true,
+	}}}
+}
+
+func greatest(_ *sqlite.FunctionContext, args []driver.Value) (driver.Value, error) {
+	var result int64
+	for i, arg := range args {
+		value, ok := arg.(int64)
+		if !ok {
+			return nil, fmt.Errorf("greatest: unsupported argument %T", arg)
+		}
+		if i == 0 || value > result {
+			result = value
+		}
+	}
+
+	return result, nil
+}
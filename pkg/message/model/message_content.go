@@ -0,0 +1,130 @@
+package message_model
+
+import (
+	"github.com/EvolutionAPI/evolution-go/pkg/utils"
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types"
+	"google.golang.org/protobuf/proto"
+)
+
+// NewMessage monta o registro completo de uma mensagem recebida ou enviada pela instância
+func NewMessage(instanceId string, info types.MessageInfo, msg *waE2E.Message) Message {
+	message := Message{
+		InstanceId: instanceId,
+		MessageID:  info.ID,
+		Timestamp:  info.Timestamp.Format("2006-01-02 15:04:05"),
+		Status:     "Received",
+		Source:     info.Chat.ToNonAD().User,
+		ChatJid:    info.Chat.ToNonAD().String(),
+		Sender:     info.Sender.ToNonAD().String(),
+		FromMe:     info.IsFromMe,
+		Type:       utils.GetMessageType(msg),
+		PushName:   info.PushName,
+		SentAt:     info.Timestamp,
+	}
+
+	if info.IsFromMe {
+		message.Status = "Sent"
+	}
+
+	if msg == nil {
+		return message
+	}
+
+	message.Text = messageText(msg)
+	message.Media = messageMedia(msg)
+
+	if contextInfo := messageContextInfo(msg); contextInfo != nil {
+		message.QuotedId = contextInfo.GetStanzaID()
+	}
+	if reaction := msg.GetReactionMessage(); reaction != nil {
+		message.QuotedId = reaction.GetKey().GetID()
+	}
+
+	if raw, err := proto.Marshal(msg); err == nil {
+		message.Raw = raw
+	}
+
+	return message
+}
+
+func messageText(msg *waE2E.Message) string {
+	switch {
+	case msg.Conversation != nil:
+		return msg.GetConversation()
+	case msg.ExtendedTextMessage != nil:
+		return msg.GetExtendedTextMessage().GetText()
+	case msg.ImageMessage != nil:
+		return msg.GetImageMessage().GetCaption()
+	case msg.VideoMessage != nil:
+		return msg.GetVideoMessage().GetCaption()
+	case msg.DocumentMessage != nil:
+		return msg.GetDocumentMessage().GetCaption()
+	case msg.ReactionMessage != nil:
+		return msg.GetReactionMessage().GetText()
+	case msg.LocationMessage != nil:
+		return msg.GetLocationMessage().GetName()
+	case msg.ContactMessage != nil:
+		return msg.GetContactMessage().GetDisplayName()
+	case msg.PollCreationMessage != nil:
+		return msg.GetPollCreationMessage().GetName()
+	case msg.PollCreationMessageV3 != nil:
+		return msg.GetPollCreationMessageV3().GetName()
+	case msg.ButtonsResponseMessage != nil:
+		return msg.GetButtonsResponseMessage().GetSelectedDisplayText()
+	case msg.ListResponseMessage != nil:
+		return msg.GetListResponseMessage().GetTitle()
+	}
+
+	return ""
+}
+
+func messageMedia(msg *waE2E.Message) *MessageMedia {
+	switch {
+	case msg.ImageMessage != nil:
+		m := msg.GetImageMessage()
+		return &MessageMedia{Type: "image", Mimetype: m.GetMimetype(), FileLength: m.GetFileLength(), Url: m.GetURL(), DirectPath: m.GetDirectPath(), MediaKey: m.GetMediaKey(), FileSHA256: m.GetFileSHA256(), FileEncSHA256: m.GetFileEncSHA256()}
+	case msg.VideoMessage != nil:
+		m := msg.GetVideoMessage()
+		return &MessageMedia{Type: "video", Mimetype: m.GetMimetype(), FileLength: m.GetFileLength(), Seconds: m.GetSeconds(), Url: m.GetURL(), DirectPath: m.GetDirectPath(), MediaKey: m.GetMediaKey(), FileSHA256: m.GetFileSHA256(), FileEncSHA256: m.GetFileEncSHA256()}
+	case msg.PtvMessage != nil:
+		m := msg.GetPtvMessage()
+		return &MessageMedia{Type: "ptv", Mimetype: m.GetMimetype(), FileLength: m.GetFileLength(), Seconds: m.GetSeconds(), Url: m.GetURL(), DirectPath: m.GetDirectPath(), MediaKey: m.GetMediaKey(), FileSHA256: m.GetFileSHA256(), FileEncSHA256: m.GetFileEncSHA256()}
+	case msg.AudioMessage != nil:
+		m := msg.GetAudioMessage()
+		return &MessageMedia{Type: "audio", Mimetype: m.GetMimetype(), FileLength: m.GetFileLength(), Seconds: m.GetSeconds(), Url: m.GetURL(), DirectPath: m.GetDirectPath(), MediaKey: m.GetMediaKey(), FileSHA256: m.GetFileSHA256(), FileEncSHA256: m.GetFileEncSHA256()}
+	case msg.DocumentMessage != nil:
+		m := msg.GetDocumentMessage()
+		return &MessageMedia{Type: "document", Mimetype: m.GetMimetype(), FileName: m.GetFileName(), FileLength: m.GetFileLength(), Url: m.GetURL(), DirectPath: m.GetDirectPath(), MediaKey: m.GetMediaKey(), FileSHA256: m.GetFileSHA256(), FileEncSHA256: m.GetFileEncSHA256()}
+	case msg.StickerMessage != nil:
+		m := msg.GetStickerMessage()
+		return &MessageMedia{Type: "sticker", Mimetype: m.GetMimetype(), FileLength: m.GetFileLength(), Url: m.GetURL(), DirectPath: m.GetDirectPath(), MediaKey: m.GetMediaKey(), FileSHA256: m.GetFileSHA256(), FileEncSHA256: m.GetFileEncSHA256()}
+	}
+
+	return nil
+}
+
+func messageContextInfo(msg *waE2E.Message) *waE2E.ContextInfo {
+	switch {
+	case msg.ExtendedTextMessage != nil:
+		return msg.GetExtendedTextMessage().GetContextInfo()
+	case msg.ImageMessage != nil:
+		return msg.GetImageMessage().GetContextInfo()
+	case msg.VideoMessage != nil:
+		return msg.GetVideoMessage().GetContextInfo()
+	case msg.PtvMessage != nil:
+		return msg.GetPtvMessage().GetContextInfo()
+	case msg.AudioMessage != nil:
+		return msg.GetAudioMessage().GetContextInfo()
+	case msg.DocumentMessage != nil:
+		return msg.GetDocumentMessage().GetContextInfo()
+	case msg.StickerMessage != nil:
+		return msg.GetStickerMessage().GetContextInfo()
+	case msg.LocationMessage != nil:
+		return msg.GetLocationMessage().GetContextInfo()
+	case msg.ContactMessage != nil:
+		return msg.GetContactMessage().GetContextInfo()
+	}
+
+	return nil
+}
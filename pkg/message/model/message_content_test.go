@@ -0,0 +1,98 @@
+package message_model
+
+import (
+	"testing"
+	"time"
+
+	"go.mau.fi/whatsmeow/proto/waCommon"
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestNewMessage(t *testing.T) {
+	info := types.MessageInfo{
+		MessageSource: types.MessageSource{
+			Chat:   types.NewJID("5511999999999", types.DefaultUserServer),
+			Sender: types.NewADJID("5511999999999", 0, 2),
+		},
+		ID:        "ABC123",
+		PushName:  "Maria",
+		Timestamp: time.Date(2024, 5, 1, 12, 30, 0, 0, time.UTC),
+	}
+
+	tests := []struct {
+		name     string
+		fromMe   bool
+		msg      *waE2E.Message
+		typ      string
+		text     string
+		quotedId string
+		media    string
+		status   string
+	}{
+		{
+			name:   "conversation",
+			msg:    &waE2E.Message{Conversation: proto.String("olá")},
+			typ:    "text",
+			text:   "olá",
+			status: "Received",
+		},
+		{
+			name:   "image with caption and quote sent by me",
+			fromMe: true,
+			msg: &waE2E.Message{ImageMessage: &waE2E.ImageMessage{
+				Caption:     proto.String("foto"),
+				Mimetype:    proto.String("image/jpeg"),
+				MediaKey:    []byte{1, 2, 3},
+				ContextInfo: &waE2E.ContextInfo{StanzaID: proto.String("QUOTED")},
+			}},
+			typ:      "image image/jpeg",
+			text:     "foto",
+			quotedId: "QUOTED",
+			media:    "image",
+			status:   "Sent",
+		},
+		{
+			name:     "reaction",
+			msg:      &waE2E.Message{ReactionMessage: &waE2E.ReactionMessage{Text: proto.String("👍"), Key: &waCommon.MessageKey{ID: proto.String("TARGET")}}},
+			typ:      "reaction",
+			text:     "👍",
+			quotedId: "TARGET",
+			status:   "Received",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info.IsFromMe = tt.fromMe
+			message := NewMessage("inst", info, tt.msg)
+
+			if message.InstanceId != "inst" || message.MessageID != "ABC123" || message.ChatJid != "5511999999999@s.whatsapp.net" || message.Sender != "5511999999999@s.whatsapp.net" {
+				t.Errorf("NewMessage() identity = %+v", message)
+			}
+			if message.Timestamp != "2024-05-01 12:30:00" || !message.SentAt.Equal(info.Timestamp) {
+				t.Errorf("NewMessage() timestamp = %q, sentAt = %v", message.Timestamp, message.SentAt)
+			}
+			if message.FromMe != tt.fromMe || message.Status != tt.status {
+				t.Errorf("NewMessage() fromMe = %v, status = %q", message.FromMe, message.Status)
+			}
+			if message.Type != tt.typ || message.Text != tt.text || message.QuotedId != tt.quotedId {
+				t.Errorf("NewMessage() type = %q, text = %q, quotedId = %q", message.Type, message.Text, message.QuotedId)
+			}
+
+			var media string
+			if message.Media != nil {
+				media = message.Media.Type
+			}
+			if media != tt.media {
+				t.Errorf("NewMessage() media = %q, want %q", media, tt.media)
+			}
+
+			var raw waE2E.Message
+			if err := proto.Unmarshal(message.Raw, &raw); err != nil || !proto.Equal(&raw, tt.msg) {
+				t.Errorf("NewMessage() raw does not round trip: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,50 @@
+package message_model
+
+import (
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrInvalidMessageCursor = errors.New("invalid cursor")
+
+// MessageCursor marca a posição de uma mensagem no histórico do chat. O ID desempata mensagens com o mesmo horário
+type MessageCursor struct {
+	SentAt    time.Time
+	MessageID string
+}
+
+// Encode gera o cursor opaco devolvido pela API
+func (c MessageCursor) Encode() string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.FormatInt(c.SentAt.UnixNano(), 10) + ":" + c.MessageID))
+}
+
+func ParseMessageCursor(value string) (*MessageCursor, error) {
+	decoded, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, ErrInvalidMessageCursor
+	}
+
+	sentAt, messageID, ok := strings.Cut(string(decoded), ":")
+	if !ok || messageID == "" {
+		return nil, ErrInvalidMessageCursor
+	}
+
+	nanos, err := strconv.ParseInt(sentAt, 10, 64)
+	if err != nil {
+		return nil, ErrInvalidMessageCursor
+	}
+
+	return &MessageCursor{SentAt: time.Unix(0, nanos).UTC(), MessageID: messageID}, nil
+}
+
+// BaseType devolve o tipo sem o mimetype da mídia ("image image/jpeg" vira "image")
+func (m *Message) BaseType() string {
+	if m.Media != nil && m.Media.Mimetype != "" {
+		return strings.TrimSuffix(m.Type, " "+m.Media.Mimetype)
+	}
+
+	return m.Type
+}
@@ -0,0 +1,42 @@
+package message_model
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMessageCursor(t *testing.T) {
+	cursor := MessageCursor{SentAt: time.Date(2024, 5, 1, 12, 30, 0, 123456000, time.UTC), MessageID: "3EB0:ABC"}
+
+	parsed, err := ParseMessageCursor(cursor.Encode())
+	if err != nil {
+		t.Fatalf("ParseMessageCursor() error = %v", err)
+	}
+	if !parsed.SentAt.Equal(cursor.SentAt) || parsed.MessageID != cursor.MessageID {
+		t.Errorf("ParseMessageCursor() = %+v, want %+v", parsed, cursor)
+	}
+
+	for _, value := range []string{"", "not base64!", "MTIz", "YWJjOklE"} {
+		if _, err := ParseMessageCursor(value); err != ErrInvalidMessageCursor {
+			t.Errorf("ParseMessageCursor(%q) error = %v, want ErrInvalidMessageCursor", value, err)
+		}
+	}
+}
+
+func TestMessageBaseType(t *testing.T) {
+	tests := []struct {
+		message Message
+		want    string
+	}{
+		{Message{Type: "text"}, "text"},
+		{Message{Type: "image image/jpeg", Media: &MessageMedia{Mimetype: "image/jpeg"}}, "image"},
+		{Message{Type: "round video video/mp4", Media: &MessageMedia{Mimetype: "video/mp4"}}, "round video"},
+		{Message{Type: "poll create"}, "poll create"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.message.BaseType(); got != tt.want {
+			t.Errorf("BaseType(%q) = %q, want %q", tt.message.Type, got, tt.want)
+		}
+	}
+}
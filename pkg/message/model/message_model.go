@@ -1,16 +1,43 @@
 package message_model
 
 import (
+	"time"
+
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
 type Message struct {
-	Id        string `json:"id" gorm:"type:uuid;primaryKey"`
-	MessageID string `json:"message_id" gorm:"unique"`
-	Timestamp string `json:"timestamp"`
-	Status    string `json:"status"`
-	Source    string `json:"source"`
+	Id         string        `json:"id" gorm:"type:uuid;primaryKey"`
+	InstanceId string        `json:"instance_id" gorm:"type:uuid;uniqueIndex:idx_messages_instance_message,priority:1;index:idx_messages_instance_chat,priority:1"`
+	MessageID  string        `json:"message_id" gorm:"uniqueIndex:idx_messages_instance_message,priority:2"`
+	Timestamp  string        `json:"timestamp"`
+	Status     string        `json:"status"`
+	Source     string        `json:"source"`
+	ChatJid    string        `json:"chat_jid" gorm:"index:idx_messages_instance_chat,priority:2"`
+	Sender     string        `json:"sender"`
+	FromMe     bool          `json:"from_me"`
+	Type       string        `json:"type"`
+	Text       string        `json:"text" gorm:"type:text"`
+	PushName   string        `json:"push_name"`
+	QuotedId   string        `json:"quoted_id"`
+	Media      *MessageMedia `json:"media,omitempty" gorm:"serializer:json"`
+	SentAt     time.Time     `json:"sent_at" gorm:"index:idx_messages_instance_chat,priority:3"`
+	Raw        []byte        `json:"-"`
+}
+
+// MessageMedia guarda o necessário para baixar a mídia depois, sem manter o arquivo no banco
+type MessageMedia struct {
+	Type          string `json:"type"`
+	Mimetype      string `json:"mimetype,omitempty"`
+	FileName      string `json:"file_name,omitempty"`
+	FileLength    uint64 `json:"file_length,omitempty"`
+	Seconds       uint32 `json:"seconds,omitempty"`
+	Url           string `json:"url,omitempty"`
+	DirectPath    string `json:"direct_path,omitempty"`
+	MediaKey      []byte `json:"media_key,omitempty"`
+	FileSHA256    []byte `json:"file_sha256,omitempty"`
+	FileEncSHA256 []byte `json:"file_enc_sha256,omitempty"`
 }
 
 func (m *Message) BeforeCreate(tx *gorm.DB) (err error) {
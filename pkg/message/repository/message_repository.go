@@ -1,14 +1,27 @@
 package message_repository
 
 import (
+	"strings"
+
 	message_model "github.com/EvolutionAPI/evolution-go/pkg/message/model"
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
 )
 
+type MessageFilter struct {
+	ChatJid string
+	Types   []string
+	FromMe  *bool
+	Cursor  *message_model.MessageCursor
+	After   bool
+	Limit   int
+}
+
 type MessageRepository interface {
 	InsertMessage(message message_model.Message) error
-	GetMessageByID(messageID string) (*message_model.Message, error)
+	SaveMessage(message message_model.Message) error
+	GetMessageByID(instanceId string, messageID string) (*message_model.Message, error)
+	ListMessages(instanceId string, filter MessageFilter) ([]message_model.Message, error)
 	DeleteAllMessages() (int64, error)
 	GetLatestMessageID(source string) (string, string, error)
 }
@@ -19,14 +32,23 @@ type messageRepository struct {
 
 func (m *messageRepository) InsertMessage(message message_model.Message) error {
 	return m.db.Clauses(clause.OnConflict{
-		Columns:   []clause.Column{{Name: "message_id"}},
+		Columns:   []clause.Column{{Name: "instance_id"}, {Name: "message_id"}},
 		DoUpdates: clause.AssignmentColumns([]string{"timestamp", "status", "source"}),
 	}).Create(&message).Error
 }
 
-func (m *messageRepository) GetMessageByID(messageID string) (*message_model.Message, error) {
+// SaveMessage grava o conteúdo da mensagem sem sobrescrever o status, que pode já ter chegado por um recibo
+func (m *messageRepository) SaveMessage(message message_model.Message) error {
+	return m.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "instance_id"}, {Name: "message_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"chat_jid", "sender", "from_me", "type", "text", "push_name", "quoted_id", "media", "sent_at", "raw"}),
+	}).Create(&message).Error
+}
+
+func (m *messageRepository) GetMessageByID(instanceId string, messageID string) (*message_model.Message, error) {
 	var message message_model.Message
-	err := m.db.Where("message_id = ?", messageID).First(&message).Error
+	// Registros anteriores ao instance_id ficam sem instância e continuam visíveis para todas
+	err := m.db.Where("message_id = ? AND (instance_id = ? OR instance_id IS NULL)", messageID, instanceId).Order("instance_id NULLS LAST").First(&message).Error
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return nil, nil
@@ -37,6 +59,46 @@ func (m *messageRepository) GetMessageByID(messageID string) (*message_model.Mes
 	return &message, nil
 }
 
+// ListMessages percorre o histórico do chat a partir do cursor: para trás (mais recentes primeiro) ou, com After, para frente
+func (m *messageRepository) ListMessages(instanceId string, filter MessageFilter) ([]message_model.Message, error) {
+	query := m.db.Where("instance_id = ? AND chat_jid = ?", instanceId, filter.ChatJid)
+
+	if len(filter.Types) > 0 {
+		// O tipo de mídia é gravado com o mimetype ("image image/jpeg"), por isso o prefixo também é aceito
+		escape := strings.NewReplacer("%", "\\%", "_", "\\_")
+
+		var conditions []string
+		var args []interface{}
+		for _, messageType := range filter.Types {
+			conditions = append(conditions, "type = ? OR type LIKE ?")
+			args = append(args, messageType, escape.Replace(messageType)+" %")
+		}
+		query = query.Where("("+strings.Join(conditions, " OR ")+")", args...)
+	}
+
+	if filter.FromMe != nil {
+		query = query.Where("from_me = ?", *filter.FromMe)
+	}
+
+	order := "sent_at DESC, message_id DESC"
+	if filter.After {
+		order = "sent_at ASC, message_id ASC"
+	}
+
+	if filter.Cursor != nil {
+		if filter.After {
+			query = query.Where("(sent_at, message_id) > (?, ?)", filter.Cursor.SentAt, filter.Cursor.MessageID)
+		} else {
+			query = query.Where("(sent_at, message_id) < (?, ?)", filter.Cursor.SentAt, filter.Cursor.MessageID)
+		}
+	}
+
+	var messages []message_model.Message
+	err := query.Order(order).Limit(filter.Limit).Find(&messages).Error
+
+	return messages, err
+}
+
 func (m *messageRepository) DeleteAllMessages() (int64, error) {
 	result := m.db.Exec("DELETE FROM messages")
 	return result.RowsAffected, result.Error
@@ -11,6 +11,7 @@ import (
 	call_handler "github.com/EvolutionAPI/evolution-go/pkg/call/handler"
 	chat_handler "github.com/EvolutionAPI/evolution-go/pkg/chat/handler"
 	community_handler "github.com/EvolutionAPI/evolution-go/pkg/community/handler"
+	event_store_handler "github.com/EvolutionAPI/evolution-go/pkg/eventStore/handler"
 	group_handler "github.com/EvolutionAPI/evolution-go/pkg/group/handler"
 	instance_handler "github.com/EvolutionAPI/evolution-go/pkg/instance/handler"
 	label_handler "github.com/EvolutionAPI/evolution-go/pkg/label/handler"
@@ -20,6 +21,7 @@ import (
 	send_handler "github.com/EvolutionAPI/evolution-go/pkg/sendMessage/handler"
 	server_handler "github.com/EvolutionAPI/evolution-go/pkg/server/handler"
 	user_handler "github.com/EvolutionAPI/evolution-go/pkg/user/handler"
+	webhook_handler "github.com/EvolutionAPI/evolution-go/pkg/webhook/handler"
 )
 
 type Routes struct {
@@ -36,6 +38,8 @@ type Routes struct {
 	labelHandler            label_handler.LabelHandler
 	newsletterHandler       newsletter_handler.NewsletterHandler
 	serverHandler           server_handler.ServerHandler
+	webhookHandler          webhook_handler.WebhookHandler
+	eventStoreHandler       event_store_handler.EventStoreHandler
 }
 
 func (r *Routes) AssignRoutes(eng *gin.Engine) {
@@ -74,6 +78,8 @@ func (r *Routes) AssignRoutes(eng *gin.Engine) {
 	})
 
 	eng.GET("/server/ok", r.serverHandler.ServerOk)
+	eng.GET("/server/event-schemas", r.serverHandler.EventSchemas)
+	eng.GET("/server/events", r.serverHandler.Events)
 
 	routes := eng.Group("/instance")
 	{
@@ -87,6 +93,11 @@ func (r *Routes) AssignRoutes(eng *gin.Engine) {
 			routes.DELETE("/proxy/:instanceId", r.instanceHandler.DeleteProxy)
 			routes.POST("/forcereconnect/:instanceId", r.instanceHandler.ForceReconnect)
 			routes.GET("/logs/:instanceId", r.instanceHandler.GetLogs)
+			routes.POST("/webhook-secret/:instanceId", r.instanceHandler.RotateWebhookSecret)
+			routes.GET("/:instanceId/webhook-dead-letters", r.webhookHandler.ListDeadLetters)
+			routes.GET("/:instanceId/webhook-dead-letters/:deadLetterId", r.webhookHandler.GetDeadLetter)
+			routes.POST("/:instanceId/webhook-dead-letters/:deadLetterId/replay", r.webhookHandler.ReplayDeadLetter)
+			routes.GET("/:instanceId/webhook-deliveries", r.webhookHandler.ListDeliveries)
 		}
 	}
 
@@ -106,6 +117,27 @@ func (r *Routes) AssignRoutes(eng *gin.Engine) {
 		}
 	}
 
+	routes = eng.Group("/webhook")
+	{
+		routes.Use(r.authMiddleware.Auth)
+		{
+			routes.POST("/targets", r.webhookHandler.CreateInstanceWebhook)
+			routes.GET("/targets", r.webhookHandler.ListInstanceWebhooks)
+			routes.GET("/targets/:webhookId", r.webhookHandler.GetInstanceWebhook)
+			routes.PUT("/targets/:webhookId", r.webhookHandler.UpdateInstanceWebhook)
+			routes.DELETE("/targets/:webhookId", r.webhookHandler.DeleteInstanceWebhook)
+		}
+	}
+
+	routes = eng.Group("/events")
+	{
+		routes.Use(r.authMiddleware.Auth)
+		{
+			routes.GET("", r.eventStoreHandler.ListEvents)
+			routes.POST("/replay", r.eventStoreHandler.ReplayEvents)
+		}
+	}
+
 	routes = eng.Group("/send")
 	{
 		routes.Use(r.authMiddleware.Auth)
@@ -164,6 +196,8 @@ func (r *Routes) AssignRoutes(eng *gin.Engine) {
 			routes.POST("/mute", r.jidValidationMiddleware.ValidateNumberField(), r.chatHandler.ChatMute)           // TODO: not working
 			routes.POST("/unmute", r.jidValidationMiddleware.ValidateNumberField(), r.chatHandler.ChatUnmute)       // TODO: not working
 			routes.POST("/history-sync", r.chatHandler.HistorySyncRequest)
+			routes.GET("/messages", r.chatHandler.ListMessages)
+			routes.GET("/list", r.chatHandler.ListChats)
 		}
 	}
 	routes = eng.Group("/group")
@@ -245,6 +279,8 @@ func NewRouter(
 	labelHandler label_handler.LabelHandler,
 	newsletterHandler newsletter_handler.NewsletterHandler,
 	serverHandler server_handler.ServerHandler,
+	webhookHandler webhook_handler.WebhookHandler,
+	eventStoreHandler event_store_handler.EventStoreHandler,
 ) *Routes {
 	return &Routes{
 		authMiddleware:          authMiddleware,
@@ -260,5 +296,7 @@ func NewRouter(
 		labelHandler:            labelHandler,
 		newsletterHandler:       newsletterHandler,
 		serverHandler:           serverHandler,
+		webhookHandler:          webhookHandler,
+		eventStoreHandler:       eventStoreHandler,
 	}
 }
@@ -18,9 +18,13 @@ import (
 	"strings"
 	"time"
 
+	chat_model "github.com/EvolutionAPI/evolution-go/pkg/chat/model"
+	chat_repository "github.com/EvolutionAPI/evolution-go/pkg/chat/repository"
 	config "github.com/EvolutionAPI/evolution-go/pkg/config"
 	instance_model "github.com/EvolutionAPI/evolution-go/pkg/instance/model"
 	logger_wrapper "github.com/EvolutionAPI/evolution-go/pkg/logger"
+	message_model "github.com/EvolutionAPI/evolution-go/pkg/message/model"
+	message_repository "github.com/EvolutionAPI/evolution-go/pkg/message/repository"
 	"github.com/EvolutionAPI/evolution-go/pkg/utils"
 	whatsmeow_service "github.com/EvolutionAPI/evolution-go/pkg/whatsmeow/service"
 	"github.com/chai2010/webp"
@@ -46,10 +50,12 @@ type SendService interface {
 }
 
 type sendService struct {
-	clientPointer    map[string]*whatsmeow.Client
-	whatsmeowService whatsmeow_service.WhatsmeowService
-	config           *config.Config
-	loggerWrapper    *logger_wrapper.LoggerManager
+	clientPointer     map[string]*whatsmeow.Client
+	messageRepository message_repository.MessageRepository
+	chatRepository    chat_repository.ChatRepository
+	whatsmeowService  whatsmeow_service.WhatsmeowService
+	config            *config.Config
+	loggerWrapper     *logger_wrapper.LoggerManager
 }
 
 type SendDataStruct struct {
@@ -1539,6 +1545,8 @@ func (s *sendService) SendButton(data *ButtonStruct, instance *instance_model.In
 		},
 	}
 
+	s.saveMessage(instance.Id, messageSent)
+
 	return messageSent, nil
 }
 
@@ -1697,6 +1705,8 @@ func (s *sendService) SendList(data *ListStruct, instance *instance_model.Instan
 		},
 	}
 
+	s.saveMessage(instance.Id, messageSent)
+
 	return messageSent, nil
 }
 
@@ -1944,6 +1954,8 @@ func (s *sendService) SendMessage(instance *instance_model.Instance, msg *waE2E.
 		},
 	}
 
+	s.saveMessage(instance.Id, messageSent)
+
 	postMap := make(map[string]interface{})
 	postMap["event"] = "SendMessage"
 
@@ -2029,9 +2041,11 @@ func (s *sendService) SendMessage(instance *instance_model.Instance, msg *waE2E.
 		return nil, err
 	}
 
+	s.whatsmeowService.StoreEvent(instance.Id, postMap["event"].(string), values)
+
 	go s.whatsmeowService.CallWebhook(instance, queueName, values)
 
-	if s.config.AmqpGlobalEnabled || s.config.NatsGlobalEnabled {
+	if s.config.GlobalEventsEnabled() {
 		go s.whatsmeowService.SendToGlobalQueues(postMap["event"].(string), values, instance.Id)
 	}
 
@@ -2039,16 +2053,40 @@ func (s *sendService) SendMessage(instance *instance_model.Instance, msg *waE2E.
 	return messageSent, nil
 }
 
+func (s *sendService) saveMessage(instanceId string, messageSent *MessageSendStruct) {
+	if !s.config.DatabaseSaveMessages {
+		return
+	}
+
+	go func() {
+		message := message_model.NewMessage(instanceId, messageSent.Info, messageSent.Message)
+		if err := s.messageRepository.SaveMessage(message); err != nil {
+			s.loggerWrapper.GetLogger(instanceId).LogError("[%s] Failed to save sent message %s: %v", instanceId, messageSent.Info.ID, err)
+			return
+		}
+
+		if chat, ok := chat_model.FromMessage(message); ok {
+			if err := s.chatRepository.SaveLastMessage(chat, true); err != nil {
+				s.loggerWrapper.GetLogger(instanceId).LogError("[%s] Failed to update chat %s: %v", instanceId, chat.Jid, err)
+			}
+		}
+	}()
+}
+
 func NewSendService(
 	clientPointer map[string]*whatsmeow.Client,
+	messageRepository message_repository.MessageRepository,
+	chatRepository chat_repository.ChatRepository,
 	whatsmeowService whatsmeow_service.WhatsmeowService,
 	config *config.Config,
 	loggerWrapper *logger_wrapper.LoggerManager,
 ) SendService {
 	return &sendService{
-		clientPointer:    clientPointer,
-		whatsmeowService: whatsmeowService,
-		config:           config,
-		loggerWrapper:    loggerWrapper,
+		clientPointer:     clientPointer,
+		messageRepository: messageRepository,
+		chatRepository:    chatRepository,
+		whatsmeowService:  whatsmeowService,
+		config:            config,
+		loggerWrapper:     loggerWrapper,
 	}
 }
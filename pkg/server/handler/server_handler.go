@@ -1,9 +1,15 @@
 package server_handler
 
-import "github.com/gin-gonic/gin"
+import (
+	event_envelope "github.com/EvolutionAPI/evolution-go/pkg/events/envelope"
+	"github.com/EvolutionAPI/evolution-go/pkg/internal/event_types"
+	"github.com/gin-gonic/gin"
+)
 
 type ServerHandler interface {
 	ServerOk(ctx *gin.Context)
+	EventSchemas(ctx *gin.Context)
+	Events(ctx *gin.Context)
 }
 
 type serverHandler struct {
@@ -16,6 +22,29 @@ func (s *serverHandler) ServerOk(ctx *gin.Context) {
 	})
 }
 
+// Event schemas
+// @Summary List event schemas
+// @Description JSON Schemas of the v1 event envelope, by event type
+// @Tags Server
+// @Produce json
+// @Success 200 {object} gin.H "success"
+// @Router /server/event-schemas [get]
+func (s *serverHandler) EventSchemas(ctx *gin.Context) {
+	ctx.JSON(200, gin.H{"message": "success", "data": event_envelope.Schemas()})
+}
+
+// Supported events
+// @Summary List supported events
+// @Description Events emitted by the API with their subscription group, queue name and payload type, plus the groups accepted in subscribe
+// @Tags Server
+// @Produce json
+// @Success 200 {object} gin.H "success"
+// @Router /server/events [get]
+func (s *serverHandler) Events(ctx *gin.Context) {
+	groups := append([]string{event_types.ALL}, event_types.AllEventTypes...)
+	ctx.JSON(200, gin.H{"message": "success", "data": gin.H{"groups": groups, "events": event_types.Events()}})
+}
+
 func NewServerHandler() ServerHandler {
 	return &serverHandler{}
 }
@@ -0,0 +1,399 @@
+package webhook_handler
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	instance_model "github.com/EvolutionAPI/evolution-go/pkg/instance/model"
+	webhook_model "github.com/EvolutionAPI/evolution-go/pkg/webhook/model"
+	webhook_repository "github.com/EvolutionAPI/evolution-go/pkg/webhook/repository"
+	webhook_service "github.com/EvolutionAPI/evolution-go/pkg/webhook/service"
+	"github.com/gin-gonic/gin"
+)
+
+type WebhookHandler interface {
+	ListDeadLetters(ctx *gin.Context)
+	GetDeadLetter(ctx *gin.Context)
+	ReplayDeadLetter(ctx *gin.Context)
+	ListDeliveries(ctx *gin.Context)
+	CreateInstanceWebhook(ctx *gin.Context)
+	ListInstanceWebhooks(ctx *gin.Context)
+	GetInstanceWebhook(ctx *gin.Context)
+	UpdateInstanceWebhook(ctx *gin.Context)
+	DeleteInstanceWebhook(ctx *gin.Context)
+}
+
+type webhookHandler struct {
+	webhookService webhook_service.WebhookService
+}
+
+type ListDeadLettersQuery struct {
+	Limit  int `form:"limit"`
+	Offset int `form:"offset"`
+}
+
+// List dead-lettered webhook deliveries
+// @Summary List webhook dead letters
+// @Description List webhook deliveries of an instance that exhausted all retries
+// @Tags Webhook
+// @Produce json
+// @Param instanceId path string true "Instance ID"
+// @Param limit query int false "Max records (default 50, max 500)"
+// @Param offset query int false "Records to skip"
+// @Success 200 {object} gin.H "success"
+// @Failure 400 {object} gin.H "Error on validation"
+// @Failure 500 {object} gin.H "Internal server error"
+// @Router /instance/{instanceId}/webhook-dead-letters [get]
+func (w *webhookHandler) ListDeadLetters(ctx *gin.Context) {
+	instanceId := ctx.Param("instanceId")
+
+	var query ListDeadLettersQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if query.Limit <= 0 {
+		query.Limit = 50 // Default: 50 registros
+	}
+	if query.Limit > 500 {
+		query.Limit = 500
+	}
+	if query.Offset < 0 {
+		query.Offset = 0
+	}
+
+	deadLetters, err := w.webhookService.ListDeadLetters(instanceId, query.Limit, query.Offset)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "success", "data": deadLetters})
+}
+
+// Inspect a dead-lettered webhook delivery
+// @Summary Get webhook dead letter
+// @Description Get a webhook delivery that exhausted all retries, including its payload and last error
+// @Tags Webhook
+// @Produce json
+// @Param instanceId path string true "Instance ID"
+// @Param deadLetterId path string true "Dead letter ID"
+// @Success 200 {object} gin.H "success"
+// @Failure 404 {object} gin.H "Dead letter not found"
+// @Failure 500 {object} gin.H "Internal server error"
+// @Router /instance/{instanceId}/webhook-dead-letters/{deadLetterId} [get]
+func (w *webhookHandler) GetDeadLetter(ctx *gin.Context) {
+	instanceId := ctx.Param("instanceId")
+	deadLetterId := ctx.Param("deadLetterId")
+
+	deadLetter, err := w.webhookService.GetDeadLetter(instanceId, deadLetterId)
+	if err != nil {
+		if errors.Is(err, webhook_service.ErrDeadLetterNotFound) {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "success", "data": deadLetter})
+}
+
+// Replay a dead-lettered webhook delivery
+// @Summary Replay webhook dead letter
+// @Description Move a dead-lettered delivery back to the webhook outbox with its attempts reset
+// @Tags Webhook
+// @Produce json
+// @Param instanceId path string true "Instance ID"
+// @Param deadLetterId path string true "Dead letter ID"
+// @Success 200 {object} gin.H "success"
+// @Failure 404 {object} gin.H "Dead letter not found"
+// @Failure 500 {object} gin.H "Internal server error"
+// @Router /instance/{instanceId}/webhook-dead-letters/{deadLetterId}/replay [post]
+func (w *webhookHandler) ReplayDeadLetter(ctx *gin.Context) {
+	instanceId := ctx.Param("instanceId")
+	deadLetterId := ctx.Param("deadLetterId")
+
+	outbox, err := w.webhookService.ReplayDeadLetter(instanceId, deadLetterId)
+	if err != nil {
+		if errors.Is(err, webhook_service.ErrDeadLetterNotFound) {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "success", "data": outbox})
+}
+
+type ListDeliveriesQuery struct {
+	Event     string `form:"event"`
+	Status    string `form:"status"`
+	StartDate string `form:"start_date"`
+	EndDate   string `form:"end_date"`
+	Limit     int    `form:"limit"`
+	Offset    int    `form:"offset"`
+}
+
+// List webhook delivery attempts
+// @Summary List webhook deliveries
+// @Description List every webhook delivery attempt of an instance with URL, event, status code, latency and response snippet
+// @Tags Webhook
+// @Produce json
+// @Param instanceId path string true "Instance ID"
+// @Param event query string false "Event type (e.g. message, receipt)"
+// @Param status query string false "Delivery status (success or failed)"
+// @Param start_date query string false "Start of the time range (RFC3339 or YYYY-MM-DD)"
+// @Param end_date query string false "End of the time range (RFC3339 or YYYY-MM-DD)"
+// @Param limit query int false "Max records (default 100, max 1000)"
+// @Param offset query int false "Records to skip"
+// @Success 200 {object} gin.H "success"
+// @Failure 400 {object} gin.H "Error on validation"
+// @Failure 500 {object} gin.H "Internal server error"
+// @Router /instance/{instanceId}/webhook-deliveries [get]
+func (w *webhookHandler) ListDeliveries(ctx *gin.Context) {
+	instanceId := ctx.Param("instanceId")
+
+	var query ListDeliveriesQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	status := strings.ToLower(query.Status)
+	if status != "" && status != webhook_model.DeliveryStatusSuccess && status != webhook_model.DeliveryStatusFailed {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "status must be success or failed"})
+		return
+	}
+
+	filter := webhook_repository.DeliveryFilter{
+		Event:  strings.ToLower(query.Event),
+		Status: status,
+		Limit:  query.Limit,
+		Offset: query.Offset,
+	}
+
+	var err error
+	if query.StartDate != "" {
+		filter.StartDate, err = parseDate(query.StartDate, false)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid start_date"})
+			return
+		}
+	}
+	if query.EndDate != "" {
+		filter.EndDate, err = parseDate(query.EndDate, true)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid end_date"})
+			return
+		}
+	}
+
+	if filter.Limit <= 0 {
+		filter.Limit = 100 // Default: 100 registros
+	}
+	if filter.Limit > 1000 {
+		filter.Limit = 1000
+	}
+	if filter.Offset < 0 {
+		filter.Offset = 0
+	}
+
+	deliveries, err := w.webhookService.ListDeliveries(instanceId, filter)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "success", "data": deliveries})
+}
+
+// parseDate aceita RFC3339 ou apenas a data; no fim do intervalo a data simples cobre o dia inteiro
+func parseDate(value string, endOfDay bool) (time.Time, error) {
+	if parsed, err := time.Parse(time.RFC3339, value); err == nil {
+		return parsed, nil
+	}
+
+	parsed, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	if endOfDay {
+		parsed = parsed.Add(24*time.Hour - time.Nanosecond)
+	}
+
+	return parsed, nil
+}
+
+// Create an additional webhook for the instance
+// @Summary Create instance webhook
+// @Description Add a webhook target with its own event subscriptions, custom headers and enabled flag
+// @Tags Webhook
+// @Accept json
+// @Produce json
+// @Param webhook body webhook_service.InstanceWebhookStruct true "Webhook data"
+// @Success 200 {object} gin.H "success"
+// @Failure 400 {object} gin.H "Error on validation"
+// @Failure 500 {object} gin.H "Internal server error"
+// @Router /webhook/targets [post]
+func (w *webhookHandler) CreateInstanceWebhook(ctx *gin.Context) {
+	instance, ok := getInstance(ctx)
+	if !ok {
+		return
+	}
+
+	var data *webhook_service.InstanceWebhookStruct
+	if err := ctx.ShouldBindJSON(&data); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if data.Url == "" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "url is required"})
+		return
+	}
+
+	webhook, err := w.webhookService.CreateInstanceWebhook(instance.Id, data)
+	if err != nil {
+		w.respondWebhookError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "success", "data": webhook})
+}
+
+// List the additional webhooks of the instance
+// @Summary List instance webhooks
+// @Description List the webhook targets of the instance
+// @Tags Webhook
+// @Produce json
+// @Success 200 {object} gin.H "success"
+// @Failure 500 {object} gin.H "Internal server error"
+// @Router /webhook/targets [get]
+func (w *webhookHandler) ListInstanceWebhooks(ctx *gin.Context) {
+	instance, ok := getInstance(ctx)
+	if !ok {
+		return
+	}
+
+	webhooks, err := w.webhookService.ListInstanceWebhooks(instance.Id)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "success", "data": webhooks})
+}
+
+// Get an additional webhook of the instance
+// @Summary Get instance webhook
+// @Description Get a webhook target of the instance
+// @Tags Webhook
+// @Produce json
+// @Param webhookId path string true "Webhook ID"
+// @Success 200 {object} gin.H "success"
+// @Failure 404 {object} gin.H "Webhook not found"
+// @Failure 500 {object} gin.H "Internal server error"
+// @Router /webhook/targets/{webhookId} [get]
+func (w *webhookHandler) GetInstanceWebhook(ctx *gin.Context) {
+	instance, ok := getInstance(ctx)
+	if !ok {
+		return
+	}
+
+	webhook, err := w.webhookService.GetInstanceWebhook(instance.Id, ctx.Param("webhookId"))
+	if err != nil {
+		w.respondWebhookError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "success", "data": webhook})
+}
+
+// Update an additional webhook of the instance
+// @Summary Update instance webhook
+// @Description Update url, subscriptions, headers or enabled flag of a webhook target. Omitted fields keep their current value
+// @Tags Webhook
+// @Accept json
+// @Produce json
+// @Param webhookId path string true "Webhook ID"
+// @Param webhook body webhook_service.InstanceWebhookStruct true "Webhook data"
+// @Success 200 {object} gin.H "success"
+// @Failure 400 {object} gin.H "Error on validation"
+// @Failure 404 {object} gin.H "Webhook not found"
+// @Failure 500 {object} gin.H "Internal server error"
+// @Router /webhook/targets/{webhookId} [put]
+func (w *webhookHandler) UpdateInstanceWebhook(ctx *gin.Context) {
+	instance, ok := getInstance(ctx)
+	if !ok {
+		return
+	}
+
+	var data *webhook_service.InstanceWebhookStruct
+	if err := ctx.ShouldBindJSON(&data); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	webhook, err := w.webhookService.UpdateInstanceWebhook(instance.Id, ctx.Param("webhookId"), data)
+	if err != nil {
+		w.respondWebhookError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "success", "data": webhook})
+}
+
+// Delete an additional webhook of the instance
+// @Summary Delete instance webhook
+// @Description Remove a webhook target of the instance
+// @Tags Webhook
+// @Produce json
+// @Param webhookId path string true "Webhook ID"
+// @Success 200 {object} gin.H "success"
+// @Failure 404 {object} gin.H "Webhook not found"
+// @Failure 500 {object} gin.H "Internal server error"
+// @Router /webhook/targets/{webhookId} [delete]
+func (w *webhookHandler) DeleteInstanceWebhook(ctx *gin.Context) {
+	instance, ok := getInstance(ctx)
+	if !ok {
+		return
+	}
+
+	if err := w.webhookService.DeleteInstanceWebhook(instance.Id, ctx.Param("webhookId")); err != nil {
+		w.respondWebhookError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "success"})
+}
+
+func (w *webhookHandler) respondWebhookError(ctx *gin.Context, err error) {
+	switch {
+	case errors.Is(err, webhook_service.ErrWebhookNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, webhook_service.ErrInvalidWebhookUrl), errors.Is(err, webhook_model.ErrInvalidWebhookBatch):
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+func getInstance(ctx *gin.Context) (*instance_model.Instance, bool) {
+	instance, ok := ctx.MustGet("instance").(*instance_model.Instance)
+	if !ok {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "instance not found"})
+		return nil, false
+	}
+
+	return instance, true
+}
+
+func NewWebhookHandler(webhookService webhook_service.WebhookService) WebhookHandler {
+	return &webhookHandler{webhookService: webhookService}
+}
@@ -0,0 +1,160 @@
+package webhook_model
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// InstanceWebhook é um destino HTTP adicional da instância, com assinatura de eventos e headers próprios
+type InstanceWebhook struct {
+	Id         string            `json:"id" gorm:"type:uuid;primaryKey"`
+	InstanceId string            `json:"instanceId" gorm:"type:uuid;index"`
+	Url        string            `json:"url"`
+	Events     string            `json:"events"`
+	Headers    map[string]string `json:"headers" gorm:"serializer:json"`
+	Batch      *WebhookBatch     `json:"batch" gorm:"serializer:json"`
+	Enabled    bool              `json:"enabled" gorm:"default:true"`
+	CreatedAt  time.Time         `json:"createdAt" gorm:"autoCreateTime"`
+	UpdatedAt  time.Time         `json:"updatedAt" gorm:"autoUpdateTime"`
+}
+
+// WebhookOutbox guarda uma entrega pendente até que o destino confirme o recebimento
+type WebhookOutbox struct {
+	Id             string            `json:"id" gorm:"type:uuid;primaryKey"`
+	InstanceId     string            `json:"instanceId" gorm:"type:uuid;index"`
+	Event          string            `json:"event"`
+	Url            string            `json:"url"`
+	Secret         string            `json:"-"` // cifrado com secret_box, como as URLs de broker da instância
+	PreviousSecret string            `json:"-"` // cifrado com secret_box
+	Headers        map[string]string `json:"-" gorm:"serializer:json"`
+	Batch          *WebhookBatch     `json:"-" gorm:"serializer:json"`
+	Payload        json.RawMessage   `json:"payload" gorm:"type:bytea"`
+	Attempts       int               `json:"attempts"`
+	LastError      string            `json:"lastError" gorm:"type:text"`
+	LastStatusCode int               `json:"lastStatusCode"`
+	NextAttemptAt  time.Time         `json:"nextAttemptAt" gorm:"index"`
+	LockedUntil    *time.Time        `json:"-"`
+	CreatedAt      time.Time         `json:"createdAt" gorm:"autoCreateTime"`
+}
+
+// WebhookDeadLetter guarda uma entrega que esgotou as tentativas, disponível para inspeção e reenvio
+type WebhookDeadLetter struct {
+	Id             string            `json:"id" gorm:"type:uuid;primaryKey"`
+	InstanceId     string            `json:"instanceId" gorm:"type:uuid;index"`
+	Event          string            `json:"event"`
+	Url            string            `json:"url"`
+	Secret         string            `json:"-"` // cifrado com secret_box, como as URLs de broker da instância
+	PreviousSecret string            `json:"-"` // cifrado com secret_box
+	Headers        map[string]string `json:"-" gorm:"serializer:json"`
+	Batch          *WebhookBatch     `json:"-" gorm:"serializer:json"` // mantido para que o reenvio volte a agrupar
+	Payload        json.RawMessage   `json:"payload" gorm:"type:bytea"`
+	Attempts       int               `json:"attempts"`
+	LastError      string            `json:"lastError" gorm:"type:text"`
+	LastStatusCode int               `json:"lastStatusCode"`
+	EnqueuedAt     time.Time         `json:"enqueuedAt"`
+	FailedAt       time.Time         `json:"failedAt" gorm:"index"`
+}
+
+const (
+	BatchMaxEvents       = 100
+	BatchMaxBytes        = 5 << 20
+	BatchMaxInterval     = time.Minute
+	BatchDefaultBytes    = 1 << 20
+	BatchDefaultInterval = time.Second
+)
+
+var ErrInvalidWebhookBatch = errors.New("invalid batch, use maxEvents up to 100, maxBytes up to 5242880 and flushIntervalMs up to 60000")
+
+// WebhookBatch agrupa os eventos de um destino em um único POST com um array JSON. O lote é enviado ao
+// atingir maxEvents ou maxBytes, ou quando flushIntervalMs passa desde o primeiro evento da janela
+type WebhookBatch struct {
+	MaxEvents       int `json:"maxEvents"`
+	MaxBytes        int `json:"maxBytes"`
+	FlushIntervalMs int `json:"flushIntervalMs"`
+}
+
+// Enabled indica se o agrupamento está ativo; maxEvents 0 ou 1 mantém a entrega individual
+func (b *WebhookBatch) Enabled() bool {
+	return b != nil && b.MaxEvents > 1
+}
+
+func (b *WebhookBatch) Validate() error {
+	if b == nil {
+		return nil
+	}
+
+	if b.MaxEvents < 0 || b.MaxEvents > BatchMaxEvents || b.MaxBytes < 0 || b.MaxBytes > BatchMaxBytes ||
+		b.FlushIntervalMs < 0 || time.Duration(b.FlushIntervalMs)*time.Millisecond > BatchMaxInterval {
+		return ErrInvalidWebhookBatch
+	}
+
+	return nil
+}
+
+// Limits retorna os limites do lote com os padrões aplicados aos campos não informados
+func (b *WebhookBatch) Limits() (int, int, time.Duration) {
+	maxBytes := b.MaxBytes
+	if maxBytes == 0 {
+		maxBytes = BatchDefaultBytes
+	}
+
+	interval := time.Duration(b.FlushIntervalMs) * time.Millisecond
+	if interval == 0 {
+		interval = BatchDefaultInterval
+	}
+
+	return b.MaxEvents, maxBytes, interval
+}
+
+const (
+	DeliveryStatusSuccess = "success"
+	DeliveryStatusFailed  = "failed"
+)
+
+// WebhookDelivery registra cada tentativa de entrega, com o resultado retornado pelo destino
+type WebhookDelivery struct {
+	Id              string    `json:"id" gorm:"type:uuid;primaryKey"`
+	InstanceId      string    `json:"instanceId" gorm:"type:uuid;index:idx_webhook_deliveries_instance_created"`
+	OutboxId        string    `json:"outboxId" gorm:"index"`
+	Event           string    `json:"event" gorm:"index"`
+	Url             string    `json:"url"`
+	Attempt         int       `json:"attempt"`
+	Status          string    `json:"status"`
+	StatusCode      int       `json:"statusCode"`
+	LatencyMs       int64     `json:"latencyMs"`
+	Error           string    `json:"error" gorm:"type:text"`
+	ResponseSnippet string    `json:"responseSnippet" gorm:"type:text"`
+	CreatedAt       time.Time `json:"createdAt" gorm:"autoCreateTime;index:idx_webhook_deliveries_instance_created;index"` // o índice próprio atende à retenção
+}
+
+func (m *InstanceWebhook) BeforeCreate(tx *gorm.DB) (err error) {
+	if m.Id == "" {
+		m.Id = uuid.New().String()
+	}
+	return
+}
+
+func (m *WebhookOutbox) BeforeCreate(tx *gorm.DB) (err error) {
+	if m.Id == "" {
+		m.Id = uuid.New().String()
+	}
+	return
+}
+
+func (m *WebhookDeadLetter) BeforeCreate(tx *gorm.DB) (err error) {
+	if m.Id == "" {
+		m.Id = uuid.New().String()
+	}
+	return
+}
+
+func (m *WebhookDelivery) BeforeCreate(tx *gorm.DB) (err error) {
+	if m.Id == "" {
+		m.Id = uuid.New().String()
+	}
+	return
+}
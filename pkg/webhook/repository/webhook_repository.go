@@ -0,0 +1,280 @@
+package webhook_repository
+
+import (
+	"fmt"
+	"time"
+
+	webhook_model "github.com/EvolutionAPI/evolution-go/pkg/webhook/model"
+	"gorm.io/gorm"
+)
+
+// DeliveryFilter define os filtros opcionais da consulta de tentativas de entrega
+type DeliveryFilter struct {
+	Event     string
+	Status    string
+	StartDate time.Time
+	EndDate   time.Time
+	Limit     int
+	Offset    int
+}
+
+type WebhookRepository interface {
+	EnqueueOutbox(outbox *webhook_model.WebhookOutbox) error
+	GetDueOutbox(now time.Time, limit int) ([]webhook_model.WebhookOutbox, error)
+	ClaimOutbox(id string, now time.Time, lockedUntil time.Time) (bool, error)
+	RenewOutboxLock(ids []string, lockedUntil time.Time) error
+	DeleteOutbox(id string) error
+	ExpediteOutbox(ids []string, now time.Time) error
+	RescheduleOutbox(outbox webhook_model.WebhookOutbox) error
+	MoveToDeadLetter(outbox webhook_model.WebhookOutbox) error
+	ListDeadLetters(instanceId string, limit int, offset int) ([]webhook_model.WebhookDeadLetter, error)
+	GetDeadLetter(instanceId string, id string) (*webhook_model.WebhookDeadLetter, error)
+	ReplayDeadLetter(deadLetter webhook_model.WebhookDeadLetter) (*webhook_model.WebhookOutbox, error)
+	InsertDelivery(delivery *webhook_model.WebhookDelivery) error
+	ListDeliveries(instanceId string, filter DeliveryFilter) ([]webhook_model.WebhookDelivery, error)
+	DeleteDeliveriesOlderThan(before time.Time) (int64, error)
+	CreateInstanceWebhook(webhook *webhook_model.InstanceWebhook) error
+	UpdateInstanceWebhook(webhook *webhook_model.InstanceWebhook) error
+	DeleteInstanceWebhook(instanceId string, id string) (bool, error)
+	GetInstanceWebhook(instanceId string, id string) (*webhook_model.InstanceWebhook, error)
+	ListInstanceWebhooks(instanceId string) ([]webhook_model.InstanceWebhook, error)
+}
+
+type webhookRepository struct {
+	db *gorm.DB
+}
+
+func (w *webhookRepository) EnqueueOutbox(outbox *webhook_model.WebhookOutbox) error {
+	return w.db.Create(outbox).Error
+}
+
+func (w *webhookRepository) GetDueOutbox(now time.Time, limit int) ([]webhook_model.WebhookOutbox, error) {
+	var outbox []webhook_model.WebhookOutbox
+	err := w.db.
+		Where("next_attempt_at <= ? AND (locked_until IS NULL OR locked_until < ?)", now, now).
+		Order("next_attempt_at ASC, created_at ASC").
+		Limit(limit).
+		Find(&outbox).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return outbox, nil
+}
+
+// ClaimOutbox reserva a entrega para este processo, evitando que outra réplica a envie ao mesmo tempo
+func (w *webhookRepository) ClaimOutbox(id string, now time.Time, lockedUntil time.Time) (bool, error) {
+	result := w.db.Model(&webhook_model.WebhookOutbox{}).
+		Where("id = ? AND (locked_until IS NULL OR locked_until < ?)", id, now).
+		Update("locked_until", lockedUntil)
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected == 1, nil
+}
+
+// RenewOutboxLock estende a reserva das entregas ainda em envio; as já reagendadas não estão mais reservadas
+func (w *webhookRepository) RenewOutboxLock(ids []string, lockedUntil time.Time) error {
+	return w.db.Model(&webhook_model.WebhookOutbox{}).
+		Where("id IN ? AND locked_until IS NOT NULL", ids).
+		Update("locked_until", lockedUntil).Error
+}
+
+func (w *webhookRepository) DeleteOutbox(id string) error {
+	return w.db.Where("id = ?", id).Delete(&webhook_model.WebhookOutbox{}).Error
+}
+
+// ExpediteOutbox antecipa as entregas de um lote que atingiu o limite antes do fim da janela
+func (w *webhookRepository) ExpediteOutbox(ids []string, now time.Time) error {
+	return w.db.Model(&webhook_model.WebhookOutbox{}).
+		Where("id IN ? AND next_attempt_at > ?", ids, now).
+		Update("next_attempt_at", now).Error
+}
+
+func (w *webhookRepository) RescheduleOutbox(outbox webhook_model.WebhookOutbox) error {
+	return w.db.Model(&webhook_model.WebhookOutbox{}).
+		Where("id = ?", outbox.Id).
+		Updates(map[string]interface{}{
+			"attempts":         outbox.Attempts,
+			"last_error":       outbox.LastError,
+			"last_status_code": outbox.LastStatusCode,
+			"next_attempt_at":  outbox.NextAttemptAt,
+			"locked_until":     nil,
+		}).Error
+}
+
+func (w *webhookRepository) MoveToDeadLetter(outbox webhook_model.WebhookOutbox) error {
+	return w.db.Transaction(func(tx *gorm.DB) error {
+		deadLetter := webhook_model.WebhookDeadLetter{
+			InstanceId:     outbox.InstanceId,
+			Event:          outbox.Event,
+			Url:            outbox.Url,
+			Secret:         outbox.Secret,
+			PreviousSecret: outbox.PreviousSecret,
+			Headers:        outbox.Headers,
+			Batch:          outbox.Batch,
+			Payload:        outbox.Payload,
+			Attempts:       outbox.Attempts,
+			LastError:      outbox.LastError,
+			LastStatusCode: outbox.LastStatusCode,
+			EnqueuedAt:     outbox.CreatedAt,
+			FailedAt:       time.Now(),
+		}
+
+		if err := tx.Create(&deadLetter).Error; err != nil {
+			return fmt.Errorf("erro ao criar dead letter: %v", err)
+		}
+
+		if err := tx.Where("id = ?", outbox.Id).Delete(&webhook_model.WebhookOutbox{}).Error; err != nil {
+			return fmt.Errorf("erro ao remover entrega do outbox: %v", err)
+		}
+
+		return nil
+	})
+}
+
+func (w *webhookRepository) ListDeadLetters(instanceId string, limit int, offset int) ([]webhook_model.WebhookDeadLetter, error) {
+	var deadLetters []webhook_model.WebhookDeadLetter
+	err := w.db.
+		Where("instance_id = ?", instanceId).
+		Order("failed_at DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&deadLetters).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return deadLetters, nil
+}
+
+func (w *webhookRepository) GetDeadLetter(instanceId string, id string) (*webhook_model.WebhookDeadLetter, error) {
+	var deadLetter webhook_model.WebhookDeadLetter
+	err := w.db.Where("instance_id = ? AND id = ?", instanceId, id).First(&deadLetter).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &deadLetter, nil
+}
+
+// ReplayDeadLetter devolve a entrega ao outbox com as tentativas zeradas
+func (w *webhookRepository) ReplayDeadLetter(deadLetter webhook_model.WebhookDeadLetter) (*webhook_model.WebhookOutbox, error) {
+	outbox := webhook_model.WebhookOutbox{
+		InstanceId:     deadLetter.InstanceId,
+		Event:          deadLetter.Event,
+		Url:            deadLetter.Url,
+		Secret:         deadLetter.Secret,
+		PreviousSecret: deadLetter.PreviousSecret,
+		Headers:        deadLetter.Headers,
+		Batch:          deadLetter.Batch,
+		Payload:        deadLetter.Payload,
+		NextAttemptAt:  time.Now(),
+	}
+
+	err := w.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&outbox).Error; err != nil {
+			return fmt.Errorf("erro ao recolocar entrega no outbox: %v", err)
+		}
+
+		if err := tx.Where("id = ?", deadLetter.Id).Delete(&webhook_model.WebhookDeadLetter{}).Error; err != nil {
+			return fmt.Errorf("erro ao remover dead letter: %v", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &outbox, nil
+}
+
+func (w *webhookRepository) InsertDelivery(delivery *webhook_model.WebhookDelivery) error {
+	return w.db.Create(delivery).Error
+}
+
+func (w *webhookRepository) ListDeliveries(instanceId string, filter DeliveryFilter) ([]webhook_model.WebhookDelivery, error) {
+	query := w.db.Where("instance_id = ?", instanceId)
+
+	if filter.Event != "" {
+		query = query.Where("event = ?", filter.Event)
+	}
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if !filter.StartDate.IsZero() {
+		query = query.Where("created_at >= ?", filter.StartDate)
+	}
+	if !filter.EndDate.IsZero() {
+		query = query.Where("created_at <= ?", filter.EndDate)
+	}
+
+	var deliveries []webhook_model.WebhookDelivery
+	err := query.
+		Order("created_at DESC").
+		Limit(filter.Limit).
+		Offset(filter.Offset).
+		Find(&deliveries).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return deliveries, nil
+}
+
+func (w *webhookRepository) DeleteDeliveriesOlderThan(before time.Time) (int64, error) {
+	result := w.db.Where("created_at < ?", before).Delete(&webhook_model.WebhookDelivery{})
+	return result.RowsAffected, result.Error
+}
+
+func (w *webhookRepository) CreateInstanceWebhook(webhook *webhook_model.InstanceWebhook) error {
+	return w.db.Create(webhook).Error
+}
+
+func (w *webhookRepository) UpdateInstanceWebhook(webhook *webhook_model.InstanceWebhook) error {
+	// Select garante que enabled=false e headers vazios também sejam persistidos
+	return w.db.Model(webhook).
+		Select("url", "events", "headers", "batch", "enabled").
+		Updates(webhook).Error
+}
+
+func (w *webhookRepository) DeleteInstanceWebhook(instanceId string, id string) (bool, error) {
+	result := w.db.Where("instance_id = ? AND id = ?", instanceId, id).Delete(&webhook_model.InstanceWebhook{})
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected > 0, nil
+}
+
+func (w *webhookRepository) GetInstanceWebhook(instanceId string, id string) (*webhook_model.InstanceWebhook, error) {
+	var webhook webhook_model.InstanceWebhook
+	err := w.db.Where("instance_id = ? AND id = ?", instanceId, id).First(&webhook).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &webhook, nil
+}
+
+func (w *webhookRepository) ListInstanceWebhooks(instanceId string) ([]webhook_model.InstanceWebhook, error) {
+	var webhooks []webhook_model.InstanceWebhook
+	err := w.db.Where("instance_id = ?", instanceId).Order("created_at ASC").Find(&webhooks).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return webhooks, nil
+}
+
+func NewWebhookRepository(db *gorm.DB) WebhookRepository {
+	return &webhookRepository{db: db}
+}
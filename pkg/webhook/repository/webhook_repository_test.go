@@ -0,0 +1,136 @@
+package webhook_repository
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/EvolutionAPI/evolution-go/pkg/internal/test_db"
+	webhook_model "github.com/EvolutionAPI/evolution-go/pkg/webhook/model"
+)
+
+const instanceId = "6f1c2d1e-9a53-4c4b-8d4e-0b6a4f1f2a10"
+
+func TestUpdateInstanceWebhook(t *testing.T) {
+	repository := NewWebhookRepository(test_db.Open(t, &webhook_model.InstanceWebhook{}))
+
+	webhook := &webhook_model.InstanceWebhook{
+		InstanceId: instanceId,
+		Url:        "https://example.com/a",
+		Events:     "MESSAGE",
+		Headers:    map[string]string{"X-Token": "a"},
+		Batch:      &webhook_model.WebhookBatch{MaxEvents: 10},
+		Enabled:    true,
+	}
+	if err := repository.CreateInstanceWebhook(webhook); err != nil {
+		t.Fatalf("CreateInstanceWebhook() error = %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		url     string
+		headers map[string]string
+		batch   *webhook_model.WebhookBatch
+		enabled bool
+	}{
+		{
+			name:    "changes batch and disables",
+			url:     "https://example.com/b",
+			headers: map[string]string{"X-Token": "b"},
+			batch:   &webhook_model.WebhookBatch{MaxEvents: 50, FlushIntervalMs: 2000},
+			enabled: false,
+		},
+		{
+			name:    "removes batch and headers",
+			url:     "https://example.com/b",
+			headers: map[string]string{},
+			batch:   nil,
+			enabled: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			webhook.Url, webhook.Headers, webhook.Batch, webhook.Enabled = tt.url, tt.headers, tt.batch, tt.enabled
+			if err := repository.UpdateInstanceWebhook(webhook); err != nil {
+				t.Fatalf("UpdateInstanceWebhook() error = %v", err)
+			}
+
+			stored, err := repository.GetInstanceWebhook(instanceId, webhook.Id)
+			if err != nil || stored == nil {
+				t.Fatalf("GetInstanceWebhook() = %v, %v", stored, err)
+			}
+			if stored.Url != tt.url || stored.Enabled != tt.enabled || !reflect.DeepEqual(stored.Headers, tt.headers) {
+				t.Errorf("stored url = %q, enabled = %v, headers = %v", stored.Url, stored.Enabled, stored.Headers)
+			}
+			if !reflect.DeepEqual(stored.Batch, tt.batch) {
+				t.Errorf("stored batch = %+v, want %+v", stored.Batch, tt.batch)
+			}
+		})
+	}
+}
+
+func TestRenewOutboxLock(t *testing.T) {
+	repository := NewWebhookRepository(test_db.Open(t, &webhook_model.WebhookOutbox{}))
+
+	now := time.Now().UTC().Truncate(time.Second)
+	claimed := &webhook_model.WebhookOutbox{InstanceId: instanceId, Url: "https://example.com", Payload: []byte("{}"), NextAttemptAt: now}
+	rescheduled := &webhook_model.WebhookOutbox{InstanceId: instanceId, Url: "https://example.com", Payload: []byte("{}"), NextAttemptAt: now}
+	for _, outbox := range []*webhook_model.WebhookOutbox{claimed, rescheduled} {
+		if err := repository.EnqueueOutbox(outbox); err != nil {
+			t.Fatalf("EnqueueOutbox() error = %v", err)
+		}
+		if ok, err := repository.ClaimOutbox(outbox.Id, now, now.Add(time.Minute)); !ok || err != nil {
+			t.Fatalf("ClaimOutbox() = %v, %v", ok, err)
+		}
+	}
+	if err := repository.RescheduleOutbox(*rescheduled); err != nil {
+		t.Fatalf("RescheduleOutbox() error = %v", err)
+	}
+
+	if err := repository.RenewOutboxLock([]string{claimed.Id, rescheduled.Id}, now.Add(time.Hour)); err != nil {
+		t.Fatalf("RenewOutboxLock() error = %v", err)
+	}
+
+	// Após o lock original expirar, só a entrega reagendada volta a ficar disponível
+	due, err := repository.GetDueOutbox(now.Add(30*time.Minute), 10)
+	if err != nil {
+		t.Fatalf("GetDueOutbox() error = %v", err)
+	}
+	if len(due) != 1 || due[0].Id != rescheduled.Id {
+		t.Errorf("GetDueOutbox() = %v, want only the rescheduled delivery", due)
+	}
+}
+
+func TestDeadLetterKeepsBatch(t *testing.T) {
+	repository := NewWebhookRepository(test_db.Open(t, &webhook_model.WebhookOutbox{}, &webhook_model.WebhookDeadLetter{}))
+
+	batch := &webhook_model.WebhookBatch{MaxEvents: 20, FlushIntervalMs: 500}
+	outbox := &webhook_model.WebhookOutbox{InstanceId: instanceId, Url: "https://example.com", Batch: batch, Payload: []byte("{}"), NextAttemptAt: time.Now().UTC()}
+	if err := repository.EnqueueOutbox(outbox); err != nil {
+		t.Fatalf("EnqueueOutbox() error = %v", err)
+	}
+	if err := repository.MoveToDeadLetter(*outbox); err != nil {
+		t.Fatalf("MoveToDeadLetter() error = %v", err)
+	}
+
+	deadLetters, err := repository.ListDeadLetters(instanceId, 10, 0)
+	if err != nil || len(deadLetters) != 1 {
+		t.Fatalf("ListDeadLetters() = %v, %v, want one dead letter", deadLetters, err)
+	}
+	if !reflect.DeepEqual(deadLetters[0].Batch, batch) {
+		t.Errorf("dead letter batch = %+v, want %+v", deadLetters[0].Batch, batch)
+	}
+
+	if _, err := repository.ReplayDeadLetter(deadLetters[0]); err != nil {
+		t.Fatalf("ReplayDeadLetter() error = %v", err)
+	}
+
+	due, err := repository.GetDueOutbox(time.Now().UTC().Add(time.Minute), 10)
+	if err != nil || len(due) != 1 {
+		t.Fatalf("GetDueOutbox() = %v, %v, want the replayed delivery", due, err)
+	}
+	if !reflect.DeepEqual(due[0].Batch, batch) {
+		t.Errorf("replayed batch = %+v, want %+v", due[0].Batch, batch)
+	}
+}
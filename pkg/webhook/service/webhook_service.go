@@ -0,0 +1,224 @@
+package webhook_service
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+
+	"github.com/EvolutionAPI/evolution-go/pkg/internal/event_types"
+	logger_wrapper "github.com/EvolutionAPI/evolution-go/pkg/logger"
+	webhook_model "github.com/EvolutionAPI/evolution-go/pkg/webhook/model"
+	webhook_repository "github.com/EvolutionAPI/evolution-go/pkg/webhook/repository"
+	whatsmeow_service "github.com/EvolutionAPI/evolution-go/pkg/whatsmeow/service"
+)
+
+var (
+	ErrDeadLetterNotFound = errors.New("dead letter not found")
+	ErrWebhookNotFound    = errors.New("webhook not found")
+	ErrInvalidWebhookUrl  = errors.New("url must be a valid http or https address")
+)
+
+type WebhookService interface {
+	ListDeadLetters(instanceId string, limit int, offset int) ([]webhook_model.WebhookDeadLetter, error)
+	GetDeadLetter(instanceId string, id string) (*webhook_model.WebhookDeadLetter, error)
+	ReplayDeadLetter(instanceId string, id string) (*webhook_model.WebhookOutbox, error)
+	ListDeliveries(instanceId string, filter webhook_repository.DeliveryFilter) ([]webhook_model.WebhookDelivery, error)
+	CreateInstanceWebhook(instanceId string, data *InstanceWebhookStruct) (*webhook_model.InstanceWebhook, error)
+	UpdateInstanceWebhook(instanceId string, id string, data *InstanceWebhookStruct) (*webhook_model.InstanceWebhook, error)
+	DeleteInstanceWebhook(instanceId string, id string) error
+	GetInstanceWebhook(instanceId string, id string) (*webhook_model.InstanceWebhook, error)
+	ListInstanceWebhooks(instanceId string) ([]webhook_model.InstanceWebhook, error)
+}
+
+type webhookService struct {
+	webhookRepository webhook_repository.WebhookRepository
+	whatsmeowService  whatsmeow_service.WhatsmeowService
+	loggerWrapper     *logger_wrapper.LoggerManager
+}
+
+type InstanceWebhookStruct struct {
+	Url       string                      `json:"url"`
+	Subscribe []string                    `json:"subscribe"`
+	Headers   map[string]string           `json:"headers"`
+	Batch     *webhook_model.WebhookBatch `json:"batch"`
+	Enabled   *bool                       `json:"enabled"`
+}
+
+func (w *webhookService) ListDeadLetters(instanceId string, limit int, offset int) ([]webhook_model.WebhookDeadLetter, error) {
+	return w.webhookRepository.ListDeadLetters(instanceId, limit, offset)
+}
+
+func (w *webhookService) GetDeadLetter(instanceId string, id string) (*webhook_model.WebhookDeadLetter, error) {
+	deadLetter, err := w.webhookRepository.GetDeadLetter(instanceId, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if deadLetter == nil {
+		return nil, ErrDeadLetterNotFound
+	}
+
+	return deadLetter, nil
+}
+
+func (w *webhookService) ReplayDeadLetter(instanceId string, id string) (*webhook_model.WebhookOutbox, error) {
+	deadLetter, err := w.GetDeadLetter(instanceId, id)
+	if err != nil {
+		return nil, err
+	}
+
+	outbox, err := w.webhookRepository.ReplayDeadLetter(*deadLetter)
+	if err != nil {
+		w.loggerWrapper.GetLogger(instanceId).LogError("[%s] Failed to replay dead letter %s: %v", instanceId, id, err)
+		return nil, err
+	}
+
+	w.loggerWrapper.GetLogger(instanceId).LogInfo("[%s] Dead letter %s moved back to webhook outbox as %s", instanceId, id, outbox.Id)
+
+	return outbox, nil
+}
+
+func (w *webhookService) ListDeliveries(instanceId string, filter webhook_repository.DeliveryFilter) ([]webhook_model.WebhookDelivery, error) {
+	return w.webhookRepository.ListDeliveries(instanceId, filter)
+}
+
+func (w *webhookService) CreateInstanceWebhook(instanceId string, data *InstanceWebhookStruct) (*webhook_model.InstanceWebhook, error) {
+	if err := validateWebhookUrl(data.Url); err != nil {
+		return nil, err
+	}
+	if err := data.Batch.Validate(); err != nil {
+		return nil, err
+	}
+
+	webhook := &webhook_model.InstanceWebhook{
+		InstanceId: instanceId,
+		Url:        data.Url,
+		Events:     w.subscribedEvents(instanceId, data.Subscribe),
+		Headers:    data.Headers,
+		Batch:      data.Batch,
+		Enabled:    data.Enabled == nil || *data.Enabled,
+	}
+
+	if err := w.webhookRepository.CreateInstanceWebhook(webhook); err != nil {
+		return nil, err
+	}
+
+	w.whatsmeowService.InvalidateInstanceWebhooks(instanceId)
+	w.loggerWrapper.GetLogger(instanceId).LogInfo("[%s] Webhook %s created for %s with events %s", instanceId, webhook.Id, webhook.Url, webhook.Events)
+
+	return webhook, nil
+}
+
+// UpdateInstanceWebhook substitui a configuração do webhook; campos omitidos mantêm o valor atual
+func (w *webhookService) UpdateInstanceWebhook(instanceId string, id string, data *InstanceWebhookStruct) (*webhook_model.InstanceWebhook, error) {
+	webhook, err := w.GetInstanceWebhook(instanceId, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if data.Url != "" {
+		if err := validateWebhookUrl(data.Url); err != nil {
+			return nil, err
+		}
+		webhook.Url = data.Url
+	}
+	if data.Subscribe != nil {
+		webhook.Events = w.subscribedEvents(instanceId, data.Subscribe)
+	}
+	if data.Headers != nil {
+		webhook.Headers = data.Headers
+	}
+	if data.Batch != nil {
+		if err := data.Batch.Validate(); err != nil {
+			return nil, err
+		}
+		webhook.Batch = data.Batch
+	}
+	if data.Enabled != nil {
+		webhook.Enabled = *data.Enabled
+	}
+
+	if err := w.webhookRepository.UpdateInstanceWebhook(webhook); err != nil {
+		return nil, err
+	}
+
+	w.whatsmeowService.InvalidateInstanceWebhooks(instanceId)
+	w.loggerWrapper.GetLogger(instanceId).LogInfo("[%s] Webhook %s updated", instanceId, webhook.Id)
+
+	return webhook, nil
+}
+
+func (w *webhookService) DeleteInstanceWebhook(instanceId string, id string) error {
+	deleted, err := w.webhookRepository.DeleteInstanceWebhook(instanceId, id)
+	if err != nil {
+		return err
+	}
+
+	if !deleted {
+		return ErrWebhookNotFound
+	}
+
+	w.whatsmeowService.InvalidateInstanceWebhooks(instanceId)
+	w.loggerWrapper.GetLogger(instanceId).LogInfo("[%s] Webhook %s deleted", instanceId, id)
+
+	return nil
+}
+
+func (w *webhookService) GetInstanceWebhook(instanceId string, id string) (*webhook_model.InstanceWebhook, error) {
+	webhook, err := w.webhookRepository.GetInstanceWebhook(instanceId, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if webhook == nil {
+		return nil, ErrWebhookNotFound
+	}
+
+	return webhook, nil
+}
+
+func (w *webhookService) ListInstanceWebhooks(instanceId string) ([]webhook_model.InstanceWebhook, error) {
+	return w.webhookRepository.ListInstanceWebhooks(instanceId)
+}
+
+// subscribedEvents segue a mesma regra do /instance/connect: vazio assina MESSAGE e ALL expande para todos os eventos
+func (w *webhookService) subscribedEvents(instanceId string, subscribe []string) string {
+	var subscribedEvents []string
+
+	if len(subscribe) == 0 {
+		subscribedEvents = append(subscribedEvents, event_types.MESSAGE)
+	} else if subscribe[0] == event_types.ALL {
+		subscribedEvents = append(subscribedEvents, event_types.AllEventTypes...)
+	} else {
+		for _, arg := range subscribe {
+			if !event_types.IsEventType(arg) {
+				w.loggerWrapper.GetLogger(instanceId).LogWarn("[%s] Message type discarded '%s'", instanceId, arg)
+				continue
+			}
+			subscribedEvents = append(subscribedEvents, arg)
+		}
+	}
+
+	return strings.Join(subscribedEvents, ",")
+}
+
+func validateWebhookUrl(rawUrl string) error {
+	parsed, err := url.ParseRequestURI(rawUrl)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return ErrInvalidWebhookUrl
+	}
+
+	return nil
+}
+
+func NewWebhookService(
+	webhookRepository webhook_repository.WebhookRepository,
+	whatsmeowService whatsmeow_service.WhatsmeowService,
+	loggerWrapper *logger_wrapper.LoggerManager,
+) WebhookService {
+	return &webhookService{
+		webhookRepository: webhookRepository,
+		whatsmeowService:  whatsmeowService,
+		loggerWrapper:     loggerWrapper,
+	}
+}
@@ -27,17 +27,25 @@ import (
 	"go.mau.fi/whatsmeow/appstate"
 	"go.mau.fi/whatsmeow/proto/waCompanionReg"
 	"go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/proto/waHistorySync"
 	"go.mau.fi/whatsmeow/store"
 	"go.mau.fi/whatsmeow/store/sqlstore"
 	"go.mau.fi/whatsmeow/types"
 	"go.mau.fi/whatsmeow/types/events"
 	waLog "go.mau.fi/whatsmeow/util/log"
 
+	chat_model "github.com/EvolutionAPI/evolution-go/pkg/chat/model"
+	chat_repository "github.com/EvolutionAPI/evolution-go/pkg/chat/repository"
 	"github.com/EvolutionAPI/evolution-go/pkg/config"
+	event_store_model "github.com/EvolutionAPI/evolution-go/pkg/eventStore/model"
+	event_store_repository "github.com/EvolutionAPI/evolution-go/pkg/eventStore/repository"
+	event_envelope "github.com/EvolutionAPI/evolution-go/pkg/events/envelope"
 	producer_interfaces "github.com/EvolutionAPI/evolution-go/pkg/events/interfaces"
+	nats_producer "github.com/EvolutionAPI/evolution-go/pkg/events/nats"
 	instance_model "github.com/EvolutionAPI/evolution-go/pkg/instance/model"
 	instance_repository "github.com/EvolutionAPI/evolution-go/pkg/instance/repository"
 	"github.com/EvolutionAPI/evolution-go/pkg/internal/event_types"
+	"github.com/EvolutionAPI/evolution-go/pkg/internal/secret_box"
 	label_model "github.com/EvolutionAPI/evolution-go/pkg/label/model"
 	label_repository "github.com/EvolutionAPI/evolution-go/pkg/label/repository"
 	logger_wrapper "github.com/EvolutionAPI/evolution-go/pkg/logger"
@@ -45,6 +53,8 @@ import (
 	message_repository "github.com/EvolutionAPI/evolution-go/pkg/message/repository"
 	storage_interfaces "github.com/EvolutionAPI/evolution-go/pkg/storage/interfaces"
 	"github.com/EvolutionAPI/evolution-go/pkg/utils"
+	webhook_model "github.com/EvolutionAPI/evolution-go/pkg/webhook/model"
+	webhook_repository "github.com/EvolutionAPI/evolution-go/pkg/webhook/repository"
 )
 
 type WhatsmeowService interface {
@@ -54,10 +64,14 @@ type WhatsmeowService interface {
 	ReconnectClient(instanceId string) error
 	ClearInstanceCache(instanceId string, token string) error
 	CallWebhook(instance *instance_model.Instance, queueName string, jsonData []byte)
+	ReplayEvent(instance *instance_model.Instance, queueName string, jsonData []byte)
 	SendToGlobalQueues(event string, jsonData []byte, userId string)
 	ForceUpdateJid(instanceId string, number string) error
 	UpdateInstanceSettings(instanceId string) error
 	UpdateInstanceAdvancedSettings(instanceId string) error
+	InvalidateInstanceWebhooks(instanceId string)
+	StoreEvent(instanceId string, event string, jsonData []byte)
+	BrokerHealth(instance *instance_model.Instance) map[string]producer_interfaces.BrokerHealth
 }
 
 type clientVersion struct {
@@ -71,19 +85,27 @@ type whatsmeowService struct {
 	authDB             *sql.DB
 	messageRepository  message_repository.MessageRepository
 	labelRepository    label_repository.LabelRepository
+	chatRepository     chat_repository.ChatRepository
 	config             *config.Config
 	killChannel        map[string](chan bool)
 	userInfoCache      *cache.Cache
 	clientPointer      map[string]*whatsmeow.Client
 	myClientPointer    map[string]*MyClient
-	rabbitmqProducer   producer_interfaces.Producer
-	webhookProducer    producer_interfaces.Producer
+	rabbitmqProducer   producer_interfaces.BrokerProducer
+	webhookProducer    producer_interfaces.WebhookProducer
 	websocketProducer  producer_interfaces.Producer
 	sqliteDB           *sql.DB
 	exPath             string
 	mediaStorage       storage_interfaces.MediaStorage
 	processedMessages  *cache.Cache
-	natsProducer       producer_interfaces.Producer
+	natsProducer       producer_interfaces.BrokerProducer
+	kafkaProducer      producer_interfaces.Producer
+	redisProducer      producer_interfaces.Producer
+	mqttProducer       producer_interfaces.MqttProducer
+	sseProducer        producer_interfaces.Producer
+	webhookRepository  webhook_repository.WebhookRepository
+	eventStore         event_store_repository.EventStoreRepository
+	instanceWebhooks   *cache.Cache
 	loggerWrapper      *logger_wrapper.LoggerManager
 }
 
@@ -98,21 +120,25 @@ type MyClient struct {
 	webhookUrl         string
 	rabbitmqEnable     string
 	natsEnable         string
+	kafkaEnable        string
+	redisEnable        string
+	mqttEnable         string
 	websocketEnable    string
 	instanceRepository instance_repository.InstanceRepository
 	messageRepository  message_repository.MessageRepository
 	labelRepository    label_repository.LabelRepository
+	chatRepository     chat_repository.ChatRepository
 	clientPointer      map[string]*whatsmeow.Client
 	killChannel        map[string](chan bool)
 	userInfoCache      *cache.Cache
 	config             *config.Config
 	historySyncID      int32
-	rabbitmqProducer   producer_interfaces.Producer
-	webhookProducer    producer_interfaces.Producer
+	rabbitmqProducer   producer_interfaces.BrokerProducer
+	webhookProducer    producer_interfaces.WebhookProducer
 	websocketProducer  producer_interfaces.Producer
 	mediaStorage       storage_interfaces.MediaStorage
 	processedMessages  *cache.Cache
-	natsProducer       producer_interfaces.Producer
+	natsProducer       producer_interfaces.BrokerProducer
 	loggerWrapper      *logger_wrapper.LoggerManager
 	qrcodeCount        int
 }
@@ -437,10 +463,14 @@ func (w whatsmeowService) StartClient(cd *ClientData) {
 		webhookUrl:         cd.Instance.Webhook,
 		rabbitmqEnable:     cd.Instance.RabbitmqEnable,
 		natsEnable:         cd.Instance.NatsEnable,
+		kafkaEnable:        cd.Instance.KafkaEnable,
+		redisEnable:        cd.Instance.RedisEnable,
+		mqttEnable:         cd.Instance.MqttEnable,
 		websocketEnable:    cd.Instance.WebSocketEnable,
 		instanceRepository: w.instanceRepository,
 		messageRepository:  w.messageRepository,
 		labelRepository:    w.labelRepository,
+		chatRepository:     w.chatRepository,
 		userInfoCache:      w.userInfoCache,
 		clientPointer:      w.clientPointer,
 		killChannel:        w.killChannel,
@@ -601,8 +631,10 @@ func (w whatsmeowService) StartClient(cd *ClientData) {
 						queueName := strings.ToLower(fmt.Sprintf("%s.%s", cd.Instance.Id, postMap["event"]))
 						values, err := json.Marshal(postMap)
 						if err == nil {
+							w.StoreEvent(cd.Instance.Id, postMap["event"].(string), values)
+
 							go w.CallWebhook(cd.Instance, queueName, values)
-							if mycli.config.AmqpGlobalEnabled || mycli.config.NatsGlobalEnabled {
+							if mycli.config.GlobalEventsEnabled() {
 								go mycli.service.SendToGlobalQueues(postMap["event"].(string), values, mycli.userID)
 							}
 						}
@@ -656,9 +688,11 @@ func (w whatsmeowService) StartClient(cd *ClientData) {
 						return
 					}
 
+					w.StoreEvent(cd.Instance.Id, postMap["event"].(string), values)
+
 					go w.CallWebhook(cd.Instance, queueName, values)
 
-					if mycli.config.AmqpGlobalEnabled || mycli.config.NatsGlobalEnabled {
+					if mycli.config.GlobalEventsEnabled() {
 						go mycli.service.SendToGlobalQueues(postMap["event"].(string), values, mycli.userID)
 					}
 				} else if evt.Event == "timeout" {
@@ -698,9 +732,11 @@ func (w whatsmeowService) StartClient(cd *ClientData) {
 						return
 					}
 
+					w.StoreEvent(cd.Instance.Id, postMap["event"].(string), values)
+
 					go w.CallWebhook(cd.Instance, queueName, values)
 
-					if mycli.config.AmqpGlobalEnabled || mycli.config.NatsGlobalEnabled {
+					if mycli.config.GlobalEventsEnabled() {
 						go mycli.service.SendToGlobalQueues(postMap["event"].(string), values, mycli.userID)
 					}
 				} else if evt.Event == "success" {
@@ -760,9 +796,11 @@ func (w whatsmeowService) StartClient(cd *ClientData) {
 				return
 			}
 
+			w.StoreEvent(cd.Instance.Id, postMap["event"].(string), values)
+
 			go w.CallWebhook(cd.Instance, queueName, values)
 
-			if mycli.config.AmqpGlobalEnabled || mycli.config.NatsGlobalEnabled {
+			if mycli.config.GlobalEventsEnabled() {
 				go mycli.service.SendToGlobalQueues(postMap["event"].(string), values, mycli.userID)
 			}
 
@@ -827,6 +865,82 @@ func processPresenceUpdates(mycli *MyClient) {
 	}
 }
 
+func (mycli *MyClient) saveMessage(info types.MessageInfo, msg *waE2E.Message) {
+	message := message_model.NewMessage(mycli.userID, info, msg)
+	if err := mycli.messageRepository.SaveMessage(message); err != nil {
+		mycli.loggerWrapper.GetLogger(mycli.userID).LogError("[%s] Failed to save message %s: %v", mycli.userID, info.ID, err)
+		return
+	}
+
+	if chat, ok := chat_model.FromMessage(message); ok {
+		if err := mycli.chatRepository.SaveLastMessage(chat, true); err != nil {
+			mycli.loggerWrapper.GetLogger(mycli.userID).LogError("[%s] Failed to update chat %s: %v", mycli.userID, chat.Jid, err)
+		}
+	}
+}
+
+// updateChat grava o estado da conversa recebido por app state (arquivar, fixar, silenciar, nome)
+func (mycli *MyClient) updateChat(jid types.JID, chat chat_model.Chat, columns ...string) {
+	chat.InstanceId = mycli.userID
+	chat.Jid = jid.ToNonAD().String()
+
+	if err := mycli.chatRepository.UpsertChat(chat, columns...); err != nil {
+		mycli.loggerWrapper.GetLogger(mycli.userID).LogError("[%s] Failed to update chat %s: %v", mycli.userID, chat.Jid, err)
+	}
+}
+
+// saveHistorySyncChats aproveita as conversas do history sync para preencher a lista de chats
+// com nome, não lidas, estado e a mensagem mais recente de cada uma
+func (mycli *MyClient) saveHistorySyncChats(conversations []*waHistorySync.Conversation) {
+	for _, conversation := range conversations {
+		jid, err := types.ParseJID(conversation.GetID())
+		if err != nil || jid == types.StatusBroadcastJID {
+			continue
+		}
+
+		chat := chat_model.Chat{
+			Name:         conversation.GetName(),
+			UnreadCount:  int(conversation.GetUnreadCount()),
+			Archived:     conversation.GetArchived(),
+			Pinned:       conversation.GetPinned() > 0,
+			LastActivity: time.Unix(int64(conversation.GetConversationTimestamp()), 0),
+		}
+		if chat.Name == "" {
+			chat.Name = conversation.GetDisplayName()
+		}
+		if muteEnd := conversation.GetMuteEndTime(); muteEnd > 0 {
+			mutedUntil := time.Unix(int64(muteEnd), 0)
+			chat.Muted = true
+			chat.MutedUntil = &mutedUntil
+		}
+
+		columns := []string{"unread_count", "archived", "pinned", "muted", "muted_until"}
+		if chat.Name != "" {
+			columns = append(columns, "name")
+		}
+		mycli.updateChat(jid, chat, columns...)
+
+		var latest *chat_model.Chat
+		for _, historyMsg := range conversation.GetMessages() {
+			evt, err := mycli.WAClient.ParseWebMessage(jid, historyMsg.GetMessage())
+			if err != nil {
+				continue
+			}
+
+			lastChat, ok := chat_model.FromMessage(message_model.NewMessage(mycli.userID, evt.Info, evt.Message))
+			if ok && (latest == nil || lastChat.LastActivity.After(latest.LastActivity)) {
+				latest = &lastChat
+			}
+		}
+
+		if latest != nil {
+			if err := mycli.chatRepository.SaveLastMessage(*latest, false); err != nil {
+				mycli.loggerWrapper.GetLogger(mycli.userID).LogError("[%s] Failed to update chat %s: %v", mycli.userID, latest.Jid, err)
+			}
+		}
+	}
+}
+
 func (mycli *MyClient) myEventHandler(rawEvt interface{}) {
 	userID := mycli.userID
 	postMap := make(map[string]interface{})
@@ -1119,6 +1233,18 @@ func (mycli *MyClient) myEventHandler(rawEvt interface{}) {
 			return
 		}
 
+		// Edições e revogações chegam como protocol message e não viram mensagens novas no histórico.
+		// A gravação vem antes do filtro, que controla apenas a entrega dos eventos
+		if mycli.config.DatabaseSaveMessages && evt.Message.GetProtocolMessage() == nil {
+			go mycli.saveMessage(evt.Info, evt.Message)
+		}
+
+		// Regras de filtro da instância (advanced settings)
+		if !mycli.Instance.EventFilter.AllowMessage(evt.Info.Chat.String(), evt.Info.IsFromMe, parsedMessageType, isMediaMessage(evt.Message)) {
+			mycli.loggerWrapper.GetLogger(mycli.userID).LogInfo("[%s] Message %s discarded by event filter (chat: %s, type: %s)", mycli.userID, evt.Info.ID, evt.Info.Chat.String(), parsedMessageType)
+			return
+		}
+
 		if postMap["data"] != nil {
 			jsonBytes, err := json.Marshal(postMap["data"])
 			if err != nil {
@@ -1478,6 +1604,18 @@ func (mycli *MyClient) myEventHandler(rawEvt interface{}) {
 			return
 		}
 
+		// Leitura feita em outro aparelho da conta. O estado da conversa é gravado antes do filtro,
+		// que controla apenas a entrega dos eventos
+		if evt.Type == types.ReceiptTypeReadSelf && mycli.config.DatabaseSaveMessages {
+			if err := mycli.chatRepository.MarkRead(mycli.userID, evt.Chat.ToNonAD().String(), true); err != nil {
+				mycli.loggerWrapper.GetLogger(mycli.userID).LogError("[%s] Failed to update chat %s: %v", mycli.userID, evt.Chat.String(), err)
+			}
+		}
+
+		if !mycli.Instance.EventFilter.AllowChat(evt.Chat.String()) {
+			return
+		}
+
 		mycli.loggerWrapper.GetLogger(mycli.userID).LogInfo("[%s] Receipt received with ID: %s from %s with type %s", mycli.userID, evt.MessageIDs[0], evt.SourceString(), evt.Type)
 
 		if evt.Type == types.ReceiptTypeRead || evt.Type == types.ReceiptTypeReadSelf {
@@ -1496,6 +1634,7 @@ func (mycli *MyClient) myEventHandler(rawEvt interface{}) {
 
 					var message message_model.Message
 
+					message.InstanceId = mycli.userID
 					message.MessageID = v
 					message.Timestamp = evt.Timestamp.Format("2006-01-02 15:04:05")
 					message.Status = "Read"
@@ -1513,6 +1652,7 @@ func (mycli *MyClient) myEventHandler(rawEvt interface{}) {
 
 			var message message_model.Message
 
+			message.InstanceId = mycli.userID
 			message.MessageID = evt.MessageIDs[0]
 			message.Timestamp = evt.Timestamp.Format("2006-01-02 15:04:05")
 			message.Status = "Delivered"
@@ -1560,11 +1700,19 @@ func (mycli *MyClient) myEventHandler(rawEvt interface{}) {
 		dataMap["FromFullSync"] = evt.FromFullSync
 		postMap["data"] = dataMap
 
+		if mycli.config.DatabaseSaveMessages {
+			mycli.updateChat(evt.JID, chat_model.Chat{Archived: evt.Action.GetArchived()}, "archived")
+		}
+
 		mycli.loggerWrapper.GetLogger(mycli.userID).LogInfo("[%s] Chat archived", mycli.userID)
 	case *events.HistorySync:
 		doWebhook = true
 		postMap["event"] = "HistorySync"
 
+		if mycli.config.DatabaseSaveMessages {
+			go mycli.saveHistorySyncChats(evt.Data.GetConversations())
+		}
+
 		mycli.loggerWrapper.GetLogger(mycli.userID).LogInfo("[%s] History sync event received %+v", mycli.userID, evt.Data.SyncType)
 	case *events.AppState:
 		mycli.loggerWrapper.GetLogger(mycli.userID).LogInfo("[%s] App state event received %+v", mycli.userID, evt)
@@ -1623,10 +1771,12 @@ func (mycli *MyClient) myEventHandler(rawEvt interface{}) {
 
 			mycli.loggerWrapper.GetLogger(mycli.userID).LogInfo("[%s] ===== DISPATCHING LOGGEDOUT EVENT ===== Queue: %s", mycli.userID, queueName)
 
+			mycli.service.StoreEvent(mycli.userID, postMap["event"].(string), values)
+
 			// Enviar para webhook/RabbitMQ
 			go mycli.service.CallWebhook(mycli.Instance, queueName, values)
 
-			if mycli.config.AmqpGlobalEnabled || mycli.config.NatsGlobalEnabled {
+			if mycli.config.GlobalEventsEnabled() {
 				mycli.loggerWrapper.GetLogger(mycli.userID).LogInfo("[%s] Sending LoggedOut to global queues - AMQP: %v, NATS: %v", mycli.userID, mycli.config.AmqpGlobalEnabled, mycli.config.NatsGlobalEnabled)
 				go mycli.service.SendToGlobalQueues(postMap["event"].(string), values, mycli.userID)
 			}
@@ -1635,6 +1785,10 @@ func (mycli *MyClient) myEventHandler(rawEvt interface{}) {
 		// Agora mata o canal DEPOIS de enviar o evento
 		mycli.killChannel[mycli.userID] <- true
 	case *events.ChatPresence:
+		if !mycli.Instance.EventFilter.AllowChat(evt.Chat.String()) {
+			return
+		}
+
 		doWebhook = true
 		postMap["event"] = "ChatPresence"
 		mycli.loggerWrapper.GetLogger(mycli.userID).LogInfo("[%s] Chat presence received %+v", mycli.userID, evt)
@@ -1758,13 +1912,101 @@ func (mycli *MyClient) myEventHandler(rawEvt interface{}) {
 		doWebhook = true
 		postMap["event"] = "PushName"
 	case *events.IdentityChange:
-		doWebhook = false
+		if !mycli.Instance.EventFilter.AllowChat(evt.JID.String()) {
+			return
+		}
+
+		doWebhook = true
+		postMap["event"] = "IdentityChange"
+	case *events.Picture:
+		if !mycli.Instance.EventFilter.AllowChat(evt.JID.String()) {
+			return
+		}
+
+		doWebhook = true
+		postMap["event"] = "Picture"
+	case *events.UserAbout:
+		if !mycli.Instance.EventFilter.AllowChat(evt.JID.String()) {
+			return
+		}
+
+		doWebhook = true
+		postMap["event"] = "UserAbout"
+	case *events.Blocklist:
+		doWebhook = true
+		postMap["event"] = "Blocklist"
+
+		mycli.loggerWrapper.GetLogger(mycli.userID).LogInfo("[%s] Blocklist changed, action: %s, changes: %d", mycli.userID, evt.Action, len(evt.Changes))
+	case *events.Mute:
+		// O estado da conversa é gravado antes do filtro, que controla apenas a entrega dos eventos
+		if mycli.config.DatabaseSaveMessages {
+			chat := chat_model.Chat{Muted: evt.Action.GetMuted()}
+			// Fim negativo significa silenciado para sempre
+			if chat.Muted && evt.Action.GetMuteEndTimestamp() > 0 {
+				mutedUntil := time.UnixMilli(evt.Action.GetMuteEndTimestamp())
+				chat.MutedUntil = &mutedUntil
+			}
+			mycli.updateChat(evt.JID, chat, "muted", "muted_until")
+		}
+
+		if !mycli.Instance.EventFilter.AllowChat(evt.JID.String()) {
+			return
+		}
+
+		doWebhook = true
+		postMap["event"] = "Mute"
+	case *events.Pin:
+		if mycli.config.DatabaseSaveMessages {
+			mycli.updateChat(evt.JID, chat_model.Chat{Pinned: evt.Action.GetPinned()}, "pinned")
+		}
+
+		if !mycli.Instance.EventFilter.AllowChat(evt.JID.String()) {
+			return
+		}
+
+		doWebhook = true
+		postMap["event"] = "Pin"
+	case *events.Star:
+		if !mycli.Instance.EventFilter.AllowChat(evt.ChatJID.String()) {
+			return
+		}
+
+		doWebhook = true
+		postMap["event"] = "Star"
+	case *events.DeleteForMe:
+		if !mycli.Instance.EventFilter.AllowChat(evt.ChatJID.String()) {
+			return
+		}
+
+		doWebhook = true
+		postMap["event"] = "DeleteForMe"
+	case *events.MarkChatAsRead:
+		if mycli.config.DatabaseSaveMessages {
+			if err := mycli.chatRepository.MarkRead(mycli.userID, evt.JID.ToNonAD().String(), evt.Action.GetRead()); err != nil {
+				mycli.loggerWrapper.GetLogger(mycli.userID).LogError("[%s] Failed to update chat %s: %v", mycli.userID, evt.JID.String(), err)
+			}
+		}
+
+		if !mycli.Instance.EventFilter.AllowChat(evt.JID.String()) {
+			return
+		}
+
+		doWebhook = true
+		postMap["event"] = "MarkChatAsRead"
 	case *events.GroupInfo:
 		doWebhook = true
 		postMap["event"] = "GroupInfo"
+
+		if mycli.config.DatabaseSaveMessages && evt.Name != nil {
+			mycli.updateChat(evt.JID, chat_model.Chat{Name: evt.Name.Name}, "name")
+		}
 	case *events.JoinedGroup:
 		doWebhook = true
 		postMap["event"] = "JoinedGroup"
+
+		if mycli.config.DatabaseSaveMessages {
+			mycli.updateChat(evt.JID, chat_model.Chat{Name: evt.Name}, "name")
+		}
 	case *events.NewsletterJoin:
 		doWebhook = true
 		postMap["event"] = "NewsletterJoin"
@@ -1825,9 +2067,11 @@ func (mycli *MyClient) myEventHandler(rawEvt interface{}) {
 		dataSize := len(values)
 		mycli.loggerWrapper.GetLogger(mycli.userID).LogInfo("[%s] ===== DISPATCHING WEBHOOK ===== Event: %s, Queue: %s, DataSize: %d bytes", mycli.userID, eventType, queueName, dataSize)
 
+		mycli.service.StoreEvent(mycli.userID, eventType, values)
+
 		go mycli.service.CallWebhook(mycli.Instance, queueName, values)
 
-		if mycli.config.AmqpGlobalEnabled || mycli.config.NatsGlobalEnabled {
+		if mycli.config.GlobalEventsEnabled() {
 			mycli.loggerWrapper.GetLogger(mycli.userID).LogInfo("[%s] Sending to global queues - Event: %s, AMQP: %v, NATS: %v", mycli.userID, eventType, mycli.config.AmqpGlobalEnabled, mycli.config.NatsGlobalEnabled)
 			go mycli.service.SendToGlobalQueues(postMap["event"].(string), values, mycli.userID)
 		}
@@ -1837,6 +2081,17 @@ func (mycli *MyClient) myEventHandler(rawEvt interface{}) {
 }
 
 func (w *whatsmeowService) CallWebhook(instance *instance_model.Instance, queueName string, jsonData []byte) {
+	w.deliverEvent(instance, queueName, jsonData, true)
+}
+
+// ReplayEvent reenvia um evento guardado apenas para webhooks e brokers. WebSocket, SSE e o status
+// retido no MQTT refletem o estado atual e não recebem eventos antigos
+func (w *whatsmeowService) ReplayEvent(instance *instance_model.Instance, queueName string, jsonData []byte) {
+	w.deliverEvent(instance, queueName, jsonData, false)
+}
+
+// deliverEvent entrega o evento nos destinos da instância; live indica um evento que acabou de acontecer
+func (w *whatsmeowService) deliverEvent(instance *instance_model.Instance, queueName string, jsonData []byte, live bool) {
 	var data map[string]interface{}
 	if err := json.Unmarshal(jsonData, &data); err != nil {
 		return
@@ -1847,7 +2102,37 @@ func (w *whatsmeowService) CallWebhook(instance *instance_model.Instance, queueN
 		return
 	}
 
-	eventArray := strings.Split(instance.Events, ",")
+	subscriptions := w.parseSubscriptions(instance.Id, instance.Events)
+
+	w.loggerWrapper.GetLogger(instance.Id).LogInfo("[%s] subscriptions %s eventType %s", instance.Id, subscriptions, eventType)
+
+	// Converte para o formato da instância (ou o padrão de EVENT_FORMAT): envelope v1 ou CloudEvents
+	format := instance.EventFormat
+	if format == "" {
+		format = w.config.EventFormat
+	}
+	formatted, err := event_envelope.Format(format, jsonData)
+	if err != nil {
+		w.loggerWrapper.GetLogger(instance.Id).LogError("[%s] Failed to format %s event as %s, sending raw payload: %v", instance.Id, eventType, format, err)
+	} else {
+		jsonData = formatted
+	}
+
+	if eventSubscribed(subscriptions, eventType) {
+		w.loggerWrapper.GetLogger(instance.Id).LogInfo("[%s] Event received of type %s", instance.Id, eventType)
+		w.sendToQueueOrWebhook(instance, queueName, eventType, jsonData, live)
+	}
+
+	// O status retido no MQTT acompanha a conexão mesmo sem assinar os eventos de conexão
+	if live && (instance.MqttEnable == "enabled" || instance.MqttEnable == "true") {
+		w.mqttProducer.PublishStatus(instance.Id, eventType)
+	}
+
+	w.sendToInstanceWebhooks(instance, queueName, eventType, jsonData)
+}
+
+func (w *whatsmeowService) parseSubscriptions(instanceId string, events string) []string {
+	eventArray := strings.Split(events, ",")
 
 	var subscriptions []string
 
@@ -1856,7 +2141,7 @@ func (w *whatsmeowService) CallWebhook(instance *instance_model.Instance, queueN
 	} else {
 		for _, arg := range eventArray {
 			if !event_types.IsEventType(arg) {
-				w.loggerWrapper.GetLogger(instance.Id).LogWarn("[%s] Message type discarded: %s", instance.Id, arg)
+				w.loggerWrapper.GetLogger(instanceId).LogWarn("[%s] Message type discarded: %s", instanceId, arg)
 				continue
 			}
 			if !utils.Find(subscriptions, arg) {
@@ -1866,84 +2151,99 @@ func (w *whatsmeowService) CallWebhook(instance *instance_model.Instance, queueN
 		}
 	}
 
+	return subscriptions
+}
+
+// eventSubscribed indica se o tipo de evento do whatsmeow pertence a algum grupo assinado
+func eventSubscribed(subscriptions []string, eventType string) bool {
 	if contains(subscriptions, "ALL") {
-		w.loggerWrapper.GetLogger(instance.Id).LogInfo("[%s] Event received of type %s", instance.Id, eventType)
-		w.sendToQueueOrWebhook(instance, queueName, jsonData)
+		return true
+	}
+
+	group := event_types.GroupOf(eventType)
+	return group != "" && contains(subscriptions, group)
+}
+
+// sendToInstanceWebhooks entrega o evento aos webhooks adicionais da instância que assinam o tipo recebido
+func (w *whatsmeowService) sendToInstanceWebhooks(instance *instance_model.Instance, queueName string, eventType string, jsonData []byte) {
+	webhooks, err := w.getInstanceWebhooks(instance.Id)
+	if err != nil {
+		w.loggerWrapper.GetLogger(instance.Id).LogError("[%s] Failed to load instance webhooks: %v", instance.Id, err)
 		return
 	}
 
-	w.loggerWrapper.GetLogger(instance.Id).LogInfo("[%s] subscriptions %s eventType %s", instance.Id, subscriptions, eventType)
+	instanceTarget, err := w.webhookTarget(instance)
+	if err != nil {
+		w.loggerWrapper.GetLogger(instance.Id).LogError("[%s] Failed to open webhook secret: %v", instance.Id, err)
+		return
+	}
 
-	switch eventType {
-	case "Message":
-		if contains(subscriptions, "MESSAGE") {
-			w.loggerWrapper.GetLogger(instance.Id).LogInfo("[%s] Event received of type %s", instance.Id, eventType)
-			w.sendToQueueOrWebhook(instance, queueName, jsonData)
-		}
-	case "SendMessage":
-		if contains(subscriptions, "SEND_MESSAGE") {
-			w.loggerWrapper.GetLogger(instance.Id).LogInfo("[%s] Event received of type %s", instance.Id, eventType)
-			w.sendToQueueOrWebhook(instance, queueName, jsonData)
-		}
-	case "Receipt":
-		if contains(subscriptions, "READ_RECEIPT") {
-			w.loggerWrapper.GetLogger(instance.Id).LogInfo("[%s] Event received of type %s", instance.Id, eventType)
-			w.sendToQueueOrWebhook(instance, queueName, jsonData)
-		}
-	case "Presence":
-		if contains(subscriptions, "PRESENCE") {
-			w.loggerWrapper.GetLogger(instance.Id).LogInfo("[%s] Event received of type %s", instance.Id, eventType)
-			w.sendToQueueOrWebhook(instance, queueName, jsonData)
-		}
-	case "HistorySync":
-		if contains(subscriptions, "HISTORY_SYNC") {
-			w.loggerWrapper.GetLogger(instance.Id).LogInfo("[%s] Event received of type %s", instance.Id, eventType)
-			w.sendToQueueOrWebhook(instance, queueName, jsonData)
-		}
-	case "ChatPresence", "Archive":
-		if contains(subscriptions, "CHAT_PRESENCE") {
-			w.loggerWrapper.GetLogger(instance.Id).LogInfo("[%s] Event received of type %s", instance.Id, eventType)
-			w.sendToQueueOrWebhook(instance, queueName, jsonData)
-		}
-	case "CallOffer", "CallAccept", "CallTerminate", "CallOfferNotice", "CallRelayLatency":
-		if contains(subscriptions, "CALL") {
-			w.loggerWrapper.GetLogger(instance.Id).LogInfo("[%s] Event received of type %s", instance.Id, eventType)
-			w.sendToQueueOrWebhook(instance, queueName, jsonData)
-		}
-	case "Connected", "PairSuccess", "TemporaryBan", "LoggedOut", "ConnectFailure", "Disconnected":
-		if contains(subscriptions, "CONNECTION") {
-			w.loggerWrapper.GetLogger(instance.Id).LogInfo("[%s] Event received of type %s", instance.Id, eventType)
-			w.sendToQueueOrWebhook(instance, queueName, jsonData)
-		}
-	case "LabelEdit", "LabelAssociationChat", "LabelAssociationMessage":
-		if contains(subscriptions, "LABEL") {
-			w.loggerWrapper.GetLogger(instance.Id).LogInfo("[%s] Event received of type %s", instance.Id, eventType)
-			w.sendToQueueOrWebhook(instance, queueName, jsonData)
-		}
-	case "Contact", "PushName":
-		if contains(subscriptions, "CONTACT") {
-			w.loggerWrapper.GetLogger(instance.Id).LogInfo("[%s] Event received of type %s", instance.Id, eventType)
-			w.sendToQueueOrWebhook(instance, queueName, jsonData)
-		}
-	case "GroupInfo", "JoinedGroup":
-		if contains(subscriptions, "GROUP") {
-			w.loggerWrapper.GetLogger(instance.Id).LogInfo("[%s] Event received of type %s", instance.Id, eventType)
-			w.sendToQueueOrWebhook(instance, queueName, jsonData)
-		}
-	case "NewsletterJoin", "NewsletterLeave":
-		if contains(subscriptions, "NEWSLETTER") {
-			w.loggerWrapper.GetLogger(instance.Id).LogInfo("[%s] Event received of type %s", instance.Id, eventType)
-			w.sendToQueueOrWebhook(instance, queueName, jsonData)
-		}
-	case "QRCode", "QRTimeout", "QRSuccess":
-		if contains(subscriptions, "QRCODE") {
-			w.loggerWrapper.GetLogger(instance.Id).LogInfo("[%s] Event received of type %s", instance.Id, eventType)
-			w.sendToQueueOrWebhook(instance, queueName, jsonData)
+	var targets []producer_interfaces.WebhookTarget
+	for _, webhook := range webhooks {
+		if !webhook.Enabled {
+			continue
+		}
+		if !eventSubscribed(w.parseSubscriptions(instance.Id, webhook.Events), eventType) {
+			continue
 		}
 
-	default:
+		target := instanceTarget
+		target.Url = webhook.Url
+		target.Headers = webhook.Headers
+		target.Batch = webhook.Batch
+		targets = append(targets, target)
+	}
+
+	if len(targets) == 0 {
+		return
+	}
+
+	err = w.webhookProducer.ProduceToTargets(queueName, jsonData, targets, instance.Id)
+	if err != nil {
+		w.loggerWrapper.GetLogger(instance.Id).LogError("[%s] Failed to send message to instance webhooks: %s", instance.Id, err)
+		return
+	}
+	w.loggerWrapper.GetLogger(instance.Id).LogInfo("[%s] Message sent to %d instance webhooks successfully", instance.Id, len(targets))
+}
+
+// getInstanceWebhooks consulta os webhooks adicionais com cache, invalidado a cada alteração pela API
+func (w *whatsmeowService) getInstanceWebhooks(instanceId string) ([]webhook_model.InstanceWebhook, error) {
+	if cached, found := w.instanceWebhooks.Get(instanceId); found {
+		return cached.([]webhook_model.InstanceWebhook), nil
+	}
+
+	webhooks, err := w.webhookRepository.ListInstanceWebhooks(instanceId)
+	if err != nil {
+		return nil, err
+	}
+
+	w.instanceWebhooks.Set(instanceId, webhooks, cache.DefaultExpiration)
+
+	return webhooks, nil
+}
+
+func (w *whatsmeowService) InvalidateInstanceWebhooks(instanceId string) {
+	w.instanceWebhooks.Delete(instanceId)
+}
+
+// StoreEvent grava o evento despachado no event store (EVENT_STORE_ENABLED). O horário é capturado
+// antes da gravação assíncrona para manter a ordem de despacho na consulta e no reenvio
+func (w *whatsmeowService) StoreEvent(instanceId string, event string, jsonData []byte) {
+	if !w.config.EventStoreEnabled {
+		return
+	}
+
+	stored, err := event_store_model.NewStoredEvent(instanceId, event, jsonData, time.Now())
+	if err != nil {
+		w.loggerWrapper.GetLogger(instanceId).LogError("[%s] Failed to build stored event %s: %v", instanceId, event, err)
 		return
 	}
+
+	go func() {
+		if err := w.eventStore.Insert(stored); err != nil {
+			w.loggerWrapper.GetLogger(instanceId).LogError("[%s] Failed to store event %s: %v", instanceId, event, err)
+		}
+	}()
 }
 
 func contains(subscriptions []string, event string) bool {
@@ -1955,42 +2255,143 @@ func contains(subscriptions []string, event string) bool {
 	return false
 }
 
-func (w *whatsmeowService) sendToQueueOrWebhook(instance *instance_model.Instance, queueName string, jsonData []byte) {
+// BrokerHealth retorna o estado das conexões RabbitMQ e NATS usadas pela instância, próprias ou compartilhadas
+func (w *whatsmeowService) BrokerHealth(instance *instance_model.Instance) map[string]producer_interfaces.BrokerHealth {
+	brokers := make(map[string]producer_interfaces.BrokerHealth)
+
+	brokerHealth := func(producer producer_interfaces.BrokerProducer, sealedUrl string) producer_interfaces.BrokerHealth {
+		brokerUrl, err := secret_box.Open(w.config.EncryptionKey, sealedUrl)
+		if err != nil {
+			return producer_interfaces.BrokerHealth{Dedicated: true, LastError: err.Error()}
+		}
+		return producer.Health(brokerUrl)
+	}
+
+	if instance.RabbitmqEnable == "enabled" || instance.RabbitmqEnable == "true" {
+		brokers["rabbitmq"] = brokerHealth(w.rabbitmqProducer, instance.RabbitmqUrl)
+	}
+	if instance.NatsEnable == "enabled" || instance.NatsEnable == "true" {
+		brokers["nats"] = brokerHealth(w.natsProducer, instance.NatsUrl)
+	}
+
+	return brokers
+}
+
+// natsSubjects monta a nomenclatura dos subjects NATS a partir de NATS_SUBJECT_PREFIX e NATS_SUBJECT_TEMPLATE
+func (w *whatsmeowService) natsSubjects() nats_producer.SubjectConfig {
+	return nats_producer.SubjectConfig{
+		GlobalPrefix:     w.config.NatsSubjectPrefix,
+		InstanceTemplate: w.config.NatsSubjectTemplate,
+	}
+}
+
+// sendToQueueOrWebhook entrega o evento em cada transporte habilitado na instância. A falha em um
+// transporte só é registrada, para não impedir a entrega nos demais. WebSocket e SSE recebem apenas
+// eventos ao vivo
+func (w *whatsmeowService) sendToQueueOrWebhook(instance *instance_model.Instance, queueName string, eventType string, jsonData []byte, live bool) {
 	if instance.RabbitmqEnable == "enabled" || instance.RabbitmqEnable == "true" {
-		err := w.rabbitmqProducer.Produce(queueName, jsonData, instance.RabbitmqEnable, instance.Id)
+		brokerUrl, err := secret_box.Open(w.config.EncryptionKey, instance.RabbitmqUrl)
+		if err == nil {
+			err = w.rabbitmqProducer.ProduceToUrl(brokerUrl, queueName, jsonData, instance.Id)
+		}
 		if err != nil {
 			w.loggerWrapper.GetLogger(instance.Id).LogError("[%s] Failed to send message to rabbitmq: %s", instance.Id, err)
-			return
+		} else {
+			w.loggerWrapper.GetLogger(instance.Id).LogInfo("[%s] Message sent to rabbitmq successfully", instance.Id)
 		}
-		w.loggerWrapper.GetLogger(instance.Id).LogInfo("[%s] Message sent to rabbitmq successfully", instance.Id)
 	}
 
 	if instance.NatsEnable == "enabled" || instance.NatsEnable == "true" {
-		err := w.natsProducer.Produce(queueName, jsonData, instance.NatsEnable, instance.Id)
+		subject := w.natsSubjects().Instance(instance.Id, instance.Name, eventType)
+		brokerUrl, err := secret_box.Open(w.config.EncryptionKey, instance.NatsUrl)
+		if err == nil {
+			err = w.natsProducer.ProduceToUrl(brokerUrl, subject, jsonData, instance.Id)
+		}
 		if err != nil {
 			w.loggerWrapper.GetLogger(instance.Id).LogError("[%s] Failed to send message to nats: %s", instance.Id, err)
-			return
+		} else {
+			w.loggerWrapper.GetLogger(instance.Id).LogInfo("[%s] Message sent to nats successfully", instance.Id)
+		}
+	}
+
+	if instance.KafkaEnable == "enabled" || instance.KafkaEnable == "true" {
+		err := w.kafkaProducer.Produce(queueName, jsonData, instance.KafkaEnable, instance.Id)
+		if err != nil {
+			w.loggerWrapper.GetLogger(instance.Id).LogError("[%s] Failed to send message to kafka: %s", instance.Id, err)
+		} else {
+			w.loggerWrapper.GetLogger(instance.Id).LogInfo("[%s] Message sent to kafka successfully", instance.Id)
+		}
+	}
+
+	if instance.RedisEnable == "enabled" || instance.RedisEnable == "true" {
+		err := w.redisProducer.Produce(queueName, jsonData, instance.RedisEnable, instance.Id)
+		if err != nil {
+			w.loggerWrapper.GetLogger(instance.Id).LogError("[%s] Failed to send message to redis: %s", instance.Id, err)
+		} else {
+			w.loggerWrapper.GetLogger(instance.Id).LogInfo("[%s] Message sent to redis successfully", instance.Id)
+		}
+	}
+
+	if instance.MqttEnable == "enabled" || instance.MqttEnable == "true" {
+		err := w.mqttProducer.Produce(queueName, jsonData, instance.MqttEnable, instance.Id)
+		if err != nil {
+			w.loggerWrapper.GetLogger(instance.Id).LogError("[%s] Failed to send message to mqtt: %s", instance.Id, err)
+		} else {
+			w.loggerWrapper.GetLogger(instance.Id).LogInfo("[%s] Message sent to mqtt successfully", instance.Id)
 		}
-		w.loggerWrapper.GetLogger(instance.Id).LogInfo("[%s] Message sent to nats successfully", instance.Id)
 	}
 
-	if instance.WebSocketEnable == "enabled" || instance.WebSocketEnable == "true" {
+	if live && (instance.WebSocketEnable == "enabled" || instance.WebSocketEnable == "true") {
 		err := w.websocketProducer.Produce(queueName, jsonData, instance.Id, instance.Token)
 		if err != nil {
 			w.loggerWrapper.GetLogger(instance.Id).LogError("[%s] Failed to send message to websocket: %s", instance.Id, err)
-			return
+		} else {
+			w.loggerWrapper.GetLogger(instance.Id).LogInfo("[%s] Message sent to websocket successfully", instance.Id)
+		}
+	}
+
+	// O stream SSE não tem configuração por instância: os eventos assinados ficam disponíveis em /events/stream
+	if live {
+		if err := w.sseProducer.Produce(queueName, jsonData, instance.Id, ""); err != nil {
+			w.loggerWrapper.GetLogger(instance.Id).LogError("[%s] Failed to send message to sse: %s", instance.Id, err)
 		}
-		w.loggerWrapper.GetLogger(instance.Id).LogInfo("[%s] Message sent to websocket successfully", instance.Id)
 	}
 
 	if instance.Webhook != "" && instance.Webhook != "disabled" {
-		err := w.webhookProducer.Produce(queueName, jsonData, instance.Webhook, instance.Id)
+		target, err := w.webhookTarget(instance)
+		if err == nil {
+			err = w.webhookProducer.ProduceToTarget(queueName, jsonData, target, instance.Id)
+		}
 		if err != nil {
 			w.loggerWrapper.GetLogger(instance.Id).LogError("[%s] Failed to send message to webhook: %s", instance.Id, err)
-			return
+		} else {
+			w.loggerWrapper.GetLogger(instance.Id).LogInfo("[%s] Message sent to webhook successfully", instance.Id)
+		}
+	}
+}
+
+// webhookTarget monta o destino do webhook da instância, incluindo o segredo anterior enquanto a janela de rotação estiver ativa.
+// Os segredos ficam cifrados na instância e são abertos aqui para assinar a entrega
+func (w *whatsmeowService) webhookTarget(instance *instance_model.Instance) (producer_interfaces.WebhookTarget, error) {
+	secret, err := secret_box.Open(w.config.EncryptionKey, instance.WebhookSecret)
+	if err != nil {
+		return producer_interfaces.WebhookTarget{}, err
+	}
+
+	target := producer_interfaces.WebhookTarget{
+		Url:    instance.Webhook,
+		Secret: secret,
+		Batch:  instance.WebhookBatch,
+	}
+
+	if instance.WebhookSecretPrevious != "" && instance.WebhookSecretPreviousExpiresAt != nil && time.Now().Before(*instance.WebhookSecretPreviousExpiresAt) {
+		target.PreviousSecret, err = secret_box.Open(w.config.EncryptionKey, instance.WebhookSecretPrevious)
+		if err != nil {
+			return producer_interfaces.WebhookTarget{}, err
 		}
-		w.loggerWrapper.GetLogger(instance.Id).LogInfo("[%s] Message sent to webhook successfully", instance.Id)
 	}
+
+	return target, nil
 }
 
 func (w whatsmeowService) StartInstance(instanceId string) error {
@@ -2134,125 +2535,118 @@ func getExtensionFromMimeType(mimeType string) string {
 func (w *whatsmeowService) SendToGlobalQueues(eventType string, payload []byte, userId string) {
 	w.loggerWrapper.GetLogger(userId).LogInfo("[%s] Starting sendToGlobalQueues for event: %s", userId, eventType)
 
+	// As filas globais seguem o formato padrão de EVENT_FORMAT
+	if formatted, err := event_envelope.Format(w.config.EventFormat, payload); err != nil {
+		w.loggerWrapper.GetLogger(userId).LogError("[%s] Failed to format %s event as %s, sending raw payload: %v", userId, eventType, w.config.EventFormat, err)
+	} else {
+		payload = formatted
+	}
+
+	// Grupo e nome da fila vêm do registry de eventos, compartilhado com os webhooks e o CreateGlobalQueues
+	globalEventType := event_types.GroupOf(eventType)
+	queueName := event_types.QueueOf(eventType)
+
 	// AMQP: AMQP_SPECIFIC_EVENTS tem prioridade sobre AMQP_GLOBAL_EVENTS
 	if w.config.AmqpGlobalEnabled {
 		var shouldSendToAmqp bool
-		var amqpQueueName string
 
 		// Se AMQP_SPECIFIC_EVENTS estiver configurada, ela tem prioridade
 		if len(w.config.AmqpSpecificEvents) > 0 {
 			w.loggerWrapper.GetLogger(userId).LogInfo("[%s] Using AMQP_SPECIFIC_EVENTS (priority over AMQP_GLOBAL_EVENTS)", userId)
-			// Verifica se o evento específico está na lista
-			if utils.Find(w.config.AmqpSpecificEvents, eventType) {
+			// Verifica se o evento específico está na lista, sem diferenciar maiúsculas
+			if contains(w.config.AmqpSpecificEvents, eventType) {
 				shouldSendToAmqp = true
-				amqpQueueName = strings.ToLower(eventType)
 				w.loggerWrapper.GetLogger(userId).LogInfo("[%s] Event %s found in AMQP_SPECIFIC_EVENTS", userId, eventType)
 			}
 		} else {
 			// Fallback para AMQP_GLOBAL_EVENTS (modo antigo com grupos de eventos)
 			w.loggerWrapper.GetLogger(userId).LogInfo("[%s] Using AMQP_GLOBAL_EVENTS (fallback mode)", userId)
 
-			// Mapeia o evento do Whatsmeow para o tipo de evento global
-			var globalEventType string
-			switch eventType {
-			case "Message":
-				globalEventType = "MESSAGE"
-			case "SendMessage":
-				globalEventType = "SEND_MESSAGE"
-			case "Receipt":
-				globalEventType = "READ_RECEIPT"
-			case "Presence":
-				globalEventType = "PRESENCE"
-			case "HistorySync":
-				globalEventType = "HISTORY_SYNC"
-			case "ChatPresence", "Archive":
-				globalEventType = "CHAT_PRESENCE"
-			case "CallOffer", "CallAccept", "CallTerminate", "CallOfferNotice", "CallRelayLatency":
-				globalEventType = "CALL"
-			case "Connected", "PairSuccess", "TemporaryBan", "LoggedOut", "ConnectFailure", "Disconnected":
-				globalEventType = "CONNECTION"
-			case "LabelEdit", "LabelAssociationChat", "LabelAssociationMessage":
-				globalEventType = "LABEL"
-			case "Contact", "PushName":
-				globalEventType = "CONTACT"
-			case "GroupInfo", "JoinedGroup":
-				globalEventType = "GROUP"
-			case "NewsletterJoin", "NewsletterLeave":
-				globalEventType = "NEWSLETTER"
-			case "QRCode", "QRTimeout", "QRSuccess":
-				globalEventType = "QRCODE"
-			default:
+			if globalEventType == "" {
 				w.loggerWrapper.GetLogger(userId).LogInfo("[%s] Event %s not mapped to global event type", userId, eventType)
-				return
-			}
-
-			// Verifica se o grupo de eventos está na lista
-			if utils.Find(w.config.AmqpGlobalEvents, globalEventType) {
+			} else if utils.Find(w.config.AmqpGlobalEvents, globalEventType) {
 				shouldSendToAmqp = true
-				amqpQueueName = strings.ToLower(eventType)
 				w.loggerWrapper.GetLogger(userId).LogInfo("[%s] Event group %s found in AMQP_GLOBAL_EVENTS", userId, globalEventType)
 			}
 		}
 
 		// Envia para RabbitMQ se necessário
 		if shouldSendToAmqp {
-			w.loggerWrapper.GetLogger(userId).LogInfo("[%s] Sending to AMQP queue: %s", userId, amqpQueueName)
-			err := w.rabbitmqProducer.Produce(amqpQueueName, payload, "global", userId)
+			w.loggerWrapper.GetLogger(userId).LogInfo("[%s] Sending to AMQP queue: %s", userId, queueName)
+			err := w.rabbitmqProducer.Produce(queueName, payload, "global", userId)
 			if err != nil {
-				w.loggerWrapper.GetLogger(userId).LogError("[%s] Failed to send message to RabbitMQ global queue %s: %v", userId, amqpQueueName, err)
+				w.loggerWrapper.GetLogger(userId).LogError("[%s] Failed to send message to RabbitMQ global queue %s: %v", userId, queueName, err)
 			} else {
-				w.loggerWrapper.GetLogger(userId).LogInfo("[%s] Successfully sent message to RabbitMQ global queue %s", userId, amqpQueueName)
+				w.loggerWrapper.GetLogger(userId).LogInfo("[%s] Successfully sent message to RabbitMQ global queue %s", userId, queueName)
 			}
 		} else {
 			w.loggerWrapper.GetLogger(userId).LogInfo("[%s] Event %s not configured for AMQP", userId, eventType)
 		}
 	}
 
-	// NATS: Mantém o comportamento original por enquanto (só NATS_GLOBAL_EVENTS)
+	// NATS: NATS_SPECIFIC_EVENTS tem prioridade sobre NATS_GLOBAL_EVENTS, como no AMQP
 	if w.config.NatsGlobalEnabled {
-		// Mapeia o evento para grupo (necessário para NATS por enquanto)
-		var globalEventType string
-		switch eventType {
-		case "Message":
-			globalEventType = "MESSAGE"
-		case "SendMessage":
-			globalEventType = "SEND_MESSAGE"
-		case "Receipt":
-			globalEventType = "READ_RECEIPT"
-		case "Presence":
-			globalEventType = "PRESENCE"
-		case "HistorySync":
-			globalEventType = "HISTORY_SYNC"
-		case "ChatPresence", "Archive":
-			globalEventType = "CHAT_PRESENCE"
-		case "CallOffer", "CallAccept", "CallTerminate", "CallOfferNotice", "CallRelayLatency":
-			globalEventType = "CALL"
-		case "Connected", "PairSuccess", "TemporaryBan", "LoggedOut", "ConnectFailure", "Disconnected":
-			globalEventType = "CONNECTION"
-		case "LabelEdit", "LabelAssociationChat", "LabelAssociationMessage":
-			globalEventType = "LABEL"
-		case "Contact", "PushName":
-			globalEventType = "CONTACT"
-		case "GroupInfo", "JoinedGroup":
-			globalEventType = "GROUP"
-		case "NewsletterJoin", "NewsletterLeave":
-			globalEventType = "NEWSLETTER"
-		case "QRCode", "QRTimeout", "QRSuccess":
-			globalEventType = "QRCODE"
-		default:
-			globalEventType = ""
+		var shouldSendToNats bool
+
+		if len(w.config.NatsSpecificEvents) > 0 {
+			shouldSendToNats = contains(w.config.NatsSpecificEvents, eventType)
+		} else {
+			shouldSendToNats = globalEventType != "" && utils.Find(w.config.NatsGlobalEvents, globalEventType)
+		}
+
+		if shouldSendToNats {
+			subject := w.natsSubjects().Global(eventType)
+			w.loggerWrapper.GetLogger(userId).LogInfo("[%s] Sending to NATS subject: %s", userId, subject)
+
+			err := w.natsProducer.Produce(subject, payload, "global", userId)
+			if err != nil {
+				w.loggerWrapper.GetLogger(userId).LogError("[%s] Failed to send message to NATS global subject %s: %v", userId, subject, err)
+			} else {
+				w.loggerWrapper.GetLogger(userId).LogInfo("[%s] Successfully sent message to NATS global subject %s", userId, subject)
+			}
+		} else {
+			w.loggerWrapper.GetLogger(userId).LogInfo("[%s] Event %s not configured for NATS", userId, eventType)
+		}
+	}
+
+	// Kafka: tópicos globais por tipo de evento, usando os grupos de KAFKA_GLOBAL_EVENTS
+	if w.config.KafkaGlobalEnabled {
+		if globalEventType != "" && utils.Find(w.config.KafkaGlobalEvents, globalEventType) {
+			w.loggerWrapper.GetLogger(userId).LogInfo("[%s] Sending to Kafka topic: %s", userId, queueName)
+
+			err := w.kafkaProducer.Produce(queueName, payload, "global", userId)
+			if err != nil {
+				w.loggerWrapper.GetLogger(userId).LogError("[%s] Failed to send message to Kafka global topic %s: %v", userId, queueName, err)
+			} else {
+				w.loggerWrapper.GetLogger(userId).LogInfo("[%s] Successfully sent message to Kafka global topic %s", userId, queueName)
+			}
+		}
+	}
+
+	// Redis: streams globais por tipo de evento, usando os grupos de REDIS_GLOBAL_EVENTS
+	if w.config.RedisGlobalEnabled {
+		if globalEventType != "" && utils.Find(w.config.RedisGlobalEvents, globalEventType) {
+			w.loggerWrapper.GetLogger(userId).LogInfo("[%s] Sending to Redis global stream: %s", userId, queueName)
+
+			err := w.redisProducer.Produce(queueName, payload, "global", userId)
+			if err != nil {
+				w.loggerWrapper.GetLogger(userId).LogError("[%s] Failed to send message to Redis global stream %s: %v", userId, queueName, err)
+			} else {
+				w.loggerWrapper.GetLogger(userId).LogInfo("[%s] Successfully sent message to Redis global stream %s", userId, queueName)
+			}
 		}
+	}
 
-		// Verifica se o evento está na lista de eventos globais NATS
-		if globalEventType != "" && utils.Find(w.config.NatsGlobalEvents, globalEventType) {
-			queueName := strings.ToLower(eventType)
-			w.loggerWrapper.GetLogger(userId).LogInfo("[%s] Sending to NATS subject: %s", userId, queueName)
+	// MQTT: tópicos globais por tipo de evento, usando os grupos de MQTT_GLOBAL_EVENTS
+	if w.config.MqttGlobalEnabled {
+		if globalEventType != "" && utils.Find(w.config.MqttGlobalEvents, globalEventType) {
+			w.loggerWrapper.GetLogger(userId).LogInfo("[%s] Sending to MQTT global topic: %s", userId, queueName)
 
-			err := w.natsProducer.Produce(queueName, payload, "global", userId)
+			err := w.mqttProducer.Produce(queueName, payload, "global", userId)
 			if err != nil {
-				w.loggerWrapper.GetLogger(userId).LogError("[%s] Failed to send message to NATS global subject %s: %v", userId, queueName, err)
+				w.loggerWrapper.GetLogger(userId).LogError("[%s] Failed to send message to MQTT global topic %s: %v", userId, queueName, err)
 			} else {
-				w.loggerWrapper.GetLogger(userId).LogInfo("[%s] Successfully sent message to NATS global subject %s", userId, queueName)
+				w.loggerWrapper.GetLogger(userId).LogInfo("[%s] Successfully sent message to MQTT global topic %s", userId, queueName)
 			}
 		}
 	}
@@ -2333,6 +2727,9 @@ func (w whatsmeowService) UpdateInstanceSettings(instanceId string) error {
 	myClient.webhookUrl = instance.Webhook
 	myClient.rabbitmqEnable = instance.RabbitmqEnable
 	myClient.natsEnable = instance.NatsEnable
+	myClient.kafkaEnable = instance.KafkaEnable
+	myClient.redisEnable = instance.RedisEnable
+	myClient.mqttEnable = instance.MqttEnable
 	myClient.websocketEnable = instance.WebSocketEnable
 
 	// Atualiza as subscriptions se os eventos mudaram
@@ -2434,16 +2831,23 @@ func NewWhatsmeowService(
 	authDB *sql.DB,
 	messageRepository message_repository.MessageRepository,
 	labelRepository label_repository.LabelRepository,
+	chatRepository chat_repository.ChatRepository,
 	config *config.Config,
 	killChannel map[string](chan bool),
 	clientPointer map[string]*whatsmeow.Client,
-	rabbitmqProducer producer_interfaces.Producer,
-	webhookProducer producer_interfaces.Producer,
+	rabbitmqProducer producer_interfaces.BrokerProducer,
+	webhookProducer producer_interfaces.WebhookProducer,
 	websocketProducer producer_interfaces.Producer,
 	sqliteDB *sql.DB,
 	exPath string,
 	mediaStorage storage_interfaces.MediaStorage,
-	natsProducer producer_interfaces.Producer,
+	natsProducer producer_interfaces.BrokerProducer,
+	kafkaProducer producer_interfaces.Producer,
+	redisProducer producer_interfaces.Producer,
+	mqttProducer producer_interfaces.MqttProducer,
+	sseProducer producer_interfaces.Producer,
+	webhookRepository webhook_repository.WebhookRepository,
+	eventStore event_store_repository.EventStoreRepository,
 	loggerWrapper *logger_wrapper.LoggerManager,
 ) WhatsmeowService {
 	return &whatsmeowService{
@@ -2451,6 +2855,7 @@ func NewWhatsmeowService(
 		authDB:             authDB,
 		messageRepository:  messageRepository,
 		labelRepository:    labelRepository,
+		chatRepository:     chatRepository,
 		config:             config,
 		killChannel:        killChannel,
 		userInfoCache:      cache.New(5*time.Minute, 10*time.Minute),
@@ -2464,10 +2869,27 @@ func NewWhatsmeowService(
 		mediaStorage:       mediaStorage,
 		processedMessages:  cache.New(30*time.Minute, 1*time.Hour),
 		natsProducer:       natsProducer,
+		kafkaProducer:      kafkaProducer,
+		redisProducer:      redisProducer,
+		mqttProducer:       mqttProducer,
+		sseProducer:        sseProducer,
+		webhookRepository:  webhookRepository,
+		eventStore:         eventStore,
+		instanceWebhooks:   cache.New(time.Minute, 5*time.Minute),
 		loggerWrapper:      loggerWrapper,
 	}
 }
 
+// isMediaMessage indica se a mensagem traz mídia, inclusive dentro de um documento com legenda
+func isMediaMessage(msg *waE2E.Message) bool {
+	if msg.GetDocumentWithCaptionMessage() != nil {
+		msg = msg.GetDocumentWithCaptionMessage().GetMessage()
+	}
+
+	return msg.GetImageMessage() != nil || msg.GetVideoMessage() != nil || msg.GetPtvMessage() != nil ||
+		msg.GetAudioMessage() != nil || msg.GetDocumentMessage() != nil || msg.GetStickerMessage() != nil
+}
+
 // cleanSenderID remove a parte ":numero" do sender ID para exibir apenas o remoteJid correto
 // Exemplo: "557499879409:3@s.whatsapp.net" -> "557499879409@s.whatsapp.net"
 func cleanSenderID(senderID string) string {